@@ -4,11 +4,7 @@ Copyright © 2022 Symbiosis
 package commands
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
-	"github.com/symbiosis-cloud/cli/pkg/identity"
-	"github.com/symbiosis-cloud/cli/pkg/project"
 	"github.com/symbiosis-cloud/cli/pkg/symcommand"
 	"github.com/symbiosis-cloud/symbiosis-go"
 )
@@ -20,55 +16,38 @@ type ApplyCommand struct {
 
 func (c *ApplyCommand) Execute(command *cobra.Command, args []string) error {
 
-	if len(args) == 0 {
-		return fmt.Errorf("Please provide a cluster name (sym apply <cluster>)")
-	}
-
-	clusterName := args[0]
-	_, err := c.Client.Cluster.Describe(clusterName)
-
-	if err != nil {
-		return fmt.Errorf("Cluster %s does not exist", clusterName)
-	}
-
-	deploymentFlags, err := symcommand.GetDeploymentFlags(command)
+	prune, err := command.Flags().GetBool("prune")
 
 	if err != nil {
 		return err
 	}
 
-	c.CommandOpts.Namespace = deploymentFlags.Namespace
-
-	identity, err := identity.NewClusterIdentity(c.Client, clusterName, deploymentFlags.IdentityOutputPath, merge)
+	projectConfig, err := loadClusterProject(command, args, c.Client, c.CommandOpts)
 
 	if err != nil {
 		return err
 	}
 
-	c.CommandOpts.Logger.Info().Msgf("Written identity to %s", identity.KubeConfigPath)
+	defer projectConfig.Close()
 
-	projectConfig, err := project.NewProjectConfig(deploymentFlags.File, c.CommandOpts, c.Client, identity)
-
-	if err != nil {
-		return err
-	}
-
-	err = projectConfig.Parse()
+	err = projectConfig.RunBuilders()
 
 	if err != nil {
 		return err
 	}
 
-	err = projectConfig.RunBuilders()
+	err = projectConfig.RunDeploy()
 
 	if err != nil {
 		return err
 	}
 
-	err = projectConfig.RunDeploy()
+	if prune {
+		err = pruneReleases(projectConfig, c.CommandOpts)
 
-	if err != nil {
-		return err
+		if err != nil {
+			return err
+		}
 	}
 
 	c.CommandOpts.Logger.Info().Msg("Apply finished.")
@@ -86,6 +65,7 @@ func (c *ApplyCommand) Command() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&merge, "merge", false, "Merge the generated kubeConfig file with the one on your system")
+	cmd.Flags().Bool("prune", false, "Uninstall Helm releases of the project that were removed from sym.yaml")
 
 	symcommand.SetDeploymentFlags(cmd)
 
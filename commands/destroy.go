@@ -0,0 +1,100 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type DestroyCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *DestroyCommand) Execute(command *cobra.Command, args []string) error {
+	deploymentFlags, err := symcommand.GetDeploymentFlags(command)
+
+	if err != nil {
+		return err
+	}
+
+	deleteNamespaces, err := command.Flags().GetBool("delete-namespaces")
+
+	if err != nil {
+		return err
+	}
+
+	projectConfig, err := loadClusterProject(command, args, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	prompt := fmt.Sprintf("Are you sure you want to destroy the deployments of %s in cluster %s", deploymentFlags.File, c.CommandOpts.Cluster)
+
+	if releases := projectConfig.ReleaseNames(); len(releases) > 0 {
+		prompt = fmt.Sprintf("%s (releases: %s)", prompt, strings.Join(releases, ", "))
+	}
+
+	if deleteNamespaces {
+		prompt += " and delete the namespaces created by sym"
+	}
+
+	err = output.Confirmation(prompt, c.CommandOpts.Yes)
+
+	if err != nil {
+		return err
+	}
+
+	results, destroyErr := projectConfig.Destroy(deleteNamespaces)
+
+	var data [][]interface{}
+
+	for _, result := range results {
+		data = append(data, []interface{}{result.Kind, result.Name, result.Namespace, result.Status})
+	}
+
+	// report what was removed before the error so a partial destroy is visible
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"Kind", "Name", "Namespace", "Status"},
+		Data:    data,
+	},
+		results,
+	).VariableOutput()
+
+	if destroyErr != nil {
+		return destroyErr
+	}
+
+	return err
+}
+
+func (c *DestroyCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "destroy <cluster>",
+		Short: "Uninstall everything sym.yaml deployed to a cluster",
+		Long:  `Uninstalls all Helm releases in reverse dependency order, deletes the kustomizations and retained jobs and, with --delete-namespaces, the namespaces sym created.`,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().Bool("delete-namespaces", false, "Also delete the namespaces created by sym")
+
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *DestroyCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
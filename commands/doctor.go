@@ -0,0 +1,291 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/cli/pkg/util"
+	"github.com/symbiosis-cloud/cli/pkg/util/firebase"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+const (
+	doctorPassed  = "ok"
+	doctorFailed  = "failed"
+	doctorSkipped = "skipped"
+)
+
+type DoctorCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+type doctorResult struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// doctorReport collects the check results, a check only runs when the checks it depends on passed
+type doctorReport struct {
+	results []*doctorResult
+	failed  int
+}
+
+func (r *doctorReport) check(name string, hint string, fn func() (string, error)) bool {
+	detail, err := fn()
+
+	if err != nil {
+		r.results = append(r.results, &doctorResult{name, doctorFailed, err.Error(), hint})
+		r.failed++
+		return false
+	}
+
+	r.results = append(r.results, &doctorResult{name, doctorPassed, detail, ""})
+
+	return true
+}
+
+func (r *doctorReport) skip(name string, reason string) {
+	r.results = append(r.results, &doctorResult{name, doctorSkipped, reason, ""})
+}
+
+func (c *DoctorCommand) Execute(command *cobra.Command, args []string) error {
+	file, err := command.Flags().GetString("file")
+
+	if err != nil {
+		return err
+	}
+
+	report := &doctorReport{}
+
+	report.check("Config file", "Run sym login or sym config init <apiKey> to create it", func() (string, error) {
+		_, err := os.ReadFile(viper.ConfigFileUsed())
+
+		return viper.ConfigFileUsed(), err
+	})
+
+	authenticated := report.check("Auth method", "Run sym login or sym config init <apiKey>", checkAuthMethod)
+
+	if authenticated && viper.GetString("auth.method") == "token" {
+		authenticated = report.check("Token refresh", "Your session expired, run sym login again", func() (string, error) {
+			return "Token is valid", firebase.ValidateToken(viper.GetString("auth.refresh_token"))
+		})
+	} else if authenticated {
+		report.skip("Token refresh", "Using an API key")
+	} else {
+		report.skip("Token refresh", "Not authenticated")
+	}
+
+	var client *symbiosis.Client
+
+	if authenticated {
+		authenticated = report.check("API", "Check your network connection and SYMBIOSIS_API_URL", func() (string, error) {
+			// the client is created after the token refresh so it uses the refreshed token
+			client, err = symcommand.NewClient()
+
+			if err != nil {
+				return "", err
+			}
+
+			_, err = client.Node.Types()
+
+			return fmt.Sprintf("%s is reachable", util.GetEnvOrDefault("SYMBIOSIS_API_URL", symbiosis.APIEndpoint)), err
+		})
+	} else {
+		report.skip("API", "Not authenticated")
+	}
+
+	var selectedProject *symbiosis.Project
+
+	if authenticated {
+		report.check("Project", "Select a project with --project or remove .symbiosis.project to pick it again", func() (string, error) {
+			selectedProject, err = c.resolveProject(client)
+
+			if err != nil {
+				return "", err
+			}
+
+			return selectedProject.Name, nil
+		})
+	} else {
+		report.skip("Project", "Not authenticated")
+	}
+
+	valid := false
+
+	if _, err := os.Stat(file); err == nil {
+		valid = report.check(file, fmt.Sprintf("Run sym validate --file %s for details", file), func() (string, error) {
+			problems, err := project.Validate(file)
+
+			if err != nil {
+				return "", err
+			}
+
+			if len(problems) > 0 {
+				return "", fmt.Errorf("Found %d problem(s)", len(problems))
+			}
+
+			return "Valid", nil
+		})
+	} else {
+		report.skip(file, "Not found")
+	}
+
+	if valid && selectedProject != nil {
+		c.checkRequirements(report, client, selectedProject, file)
+	} else if valid {
+		report.skip("Builder requirements", "No project selected")
+	}
+
+	var data [][]interface{}
+
+	for _, result := range report.results {
+		data = append(data, []interface{}{result.Check, result.Status, result.Detail, result.Hint})
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"Check", "Status", "Detail", "Hint"},
+		Data:    data,
+	},
+		report.results,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	if report.failed > 0 {
+		return fmt.Errorf("%d check(s) failed", report.failed)
+	}
+
+	return nil
+}
+
+// checkRequirements reports the requirements of every builder configured in sym.yaml
+func (c *DoctorCommand) checkRequirements(report *doctorReport, client *symbiosis.Client, selectedProject *symbiosis.Project, file string) {
+	c.CommandOpts.Project = selectedProject
+
+	var requirements []builder.Requirement
+
+	ok := report.check("Builder requirements", "Run sym render to check that sym.yaml renders with the project secrets", func() (string, error) {
+		projectConfig, err := project.NewProjectConfig(file, c.CommandOpts, client, nil)
+
+		if err != nil {
+			return "", err
+		}
+
+		defer projectConfig.Close()
+
+		err = projectConfig.Parse()
+
+		if err != nil {
+			return "", err
+		}
+
+		requirements, err = projectConfig.Requirements()
+
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%d requirement(s)", len(requirements)), nil
+	})
+
+	if !ok {
+		return
+	}
+
+	for _, requirement := range requirements {
+		report.check(requirement.Value(), requirement.Hint(), func() (string, error) {
+			if !requirement.Check() {
+				return "", requirement.Failed()
+			}
+
+			return "Found", nil
+		})
+	}
+}
+
+// resolveProject returns the project selected with --project or stored in .symbiosis.project
+func (c *DoctorCommand) resolveProject(client *symbiosis.Client) (*symbiosis.Project, error) {
+	if c.CommandOpts.Project != nil {
+		return c.CommandOpts.Project, nil
+	}
+
+	dir, err := os.Getwd()
+
+	if err != nil {
+		return nil, err
+	}
+
+	projectFile, err := os.ReadFile(path.Join(dir, ".symbiosis.project"))
+
+	if err != nil && os.IsNotExist(err) {
+		return nil, fmt.Errorf("No project selected")
+	} else if err != nil {
+		return nil, err
+	}
+
+	var selectedProject *symbiosis.Project
+
+	err = json.Unmarshal(projectFile, &selectedProject)
+
+	if err != nil {
+		return nil, fmt.Errorf(".symbiosis.project could not be parsed: %v", err)
+	}
+
+	return client.Project.Describe(selectedProject.Name)
+}
+
+func checkAuthMethod() (string, error) {
+	method := viper.GetString("auth.method")
+
+	switch method {
+	case "api_key":
+		if viper.GetString("auth.api_key") == "" {
+			return "", fmt.Errorf("No API key configured")
+		}
+	case "token":
+		if viper.GetString("auth.refresh_token") == "" || viper.GetString("auth.team_id") == "" {
+			return "", fmt.Errorf("Incomplete token configuration")
+		}
+	case "":
+		return "", fmt.Errorf("No auth method configured")
+	default:
+		return "", fmt.Errorf("Unknown auth method %s", method)
+	}
+
+	return method, nil
+}
+
+func (c *DoctorCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "doctor [--file sym.yaml]",
+		Short: "Check your configuration, authentication and the requirements of sym.yaml",
+		Long:  `Prints a report of all checks and exits with a non-zero code when any check failed, so it can be used in CI.`,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().String("file", "sym.yaml", "File to use (default: sym.yaml)")
+
+	return cmd
+}
+
+func (c *DoctorCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
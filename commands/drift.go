@@ -0,0 +1,204 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+const (
+	DRIFT_EVENT_CHECK    = "check"
+	DRIFT_EVENT_DRIFTED  = "drifted"
+	DRIFT_EVENT_RESOLVED = "resolved"
+	DRIFT_EVENT_ERROR    = "error"
+)
+
+// DriftEvent is written as a JSON line for every check and every drifted or resolved object with --interval
+type DriftEvent struct {
+	Time    time.Time              `json:"time"`
+	Event   string                 `json:"event"`
+	Cluster string                 `json:"cluster"`
+	Object  *project.DriftedObject `json:"object,omitempty"`
+	Drifted *int                   `json:"drifted,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+type DriftCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *DriftCommand) Execute(command *cobra.Command, args []string) error {
+	interval, err := command.Flags().GetDuration("interval")
+
+	if err != nil {
+		return err
+	}
+
+	if interval < 0 {
+		return fmt.Errorf("Invalid interval %s", interval)
+	}
+
+	projectConfig, err := loadClusterProject(command, args, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	check, err := projectConfig.NewDriftCheck()
+
+	if err != nil {
+		return err
+	}
+
+	defer check.Close()
+
+	if interval > 0 {
+		return c.watch(check, interval)
+	}
+
+	drifted, err := check.Run(context.TODO())
+
+	if err != nil {
+		return err
+	}
+
+	var data [][]interface{}
+
+	for _, object := range drifted {
+		var fields []string
+
+		for _, field := range object.Fields {
+			fields = append(fields, fmt.Sprintf("%s (%v -> %v)", field.Path, field.Desired, field.Live))
+		}
+
+		data = append(data, []interface{}{object.Source, object.Kind, object.Name, object.Namespace, object.Status, strings.Join(fields, ", ")})
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"Source", "Kind", "Name", "Namespace", "Status", "Fields (desired -> live)"},
+		Data:    data,
+	},
+		drifted,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	if len(drifted) == 0 {
+		c.CommandOpts.Logger.Info().Msg("No drift, the cluster matches sym.yaml.")
+	} else {
+		c.CommandOpts.Logger.Warn().Msgf("%d objects drifted from sym.yaml", len(drifted))
+	}
+
+	return nil
+}
+
+// watch checks for drift every interval until interrupted. Drifted objects are reported on every check, an
+// object that matches again is reported once as resolved.
+func (c *DriftCommand) watch(check *project.DriftCheck, interval time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	encoder := json.NewEncoder(os.Stdout)
+
+	emit := func(event *DriftEvent) {
+		event.Time = time.Now().UTC()
+		event.Cluster = c.CommandOpts.Cluster
+
+		if err := encoder.Encode(event); err != nil {
+			c.CommandOpts.Logger.Error().Msgf("Could not write event: %v", err)
+		}
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Checking for drift every %s", interval)
+
+	previous := map[string]*project.DriftedObject{}
+
+	for {
+		drifted, err := check.Run(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			emit(&DriftEvent{Event: DRIFT_EVENT_ERROR, Error: err.Error()})
+		} else {
+			current := map[string]*project.DriftedObject{}
+
+			for _, object := range drifted {
+				current[object.Key()] = object
+				emit(&DriftEvent{Event: DRIFT_EVENT_DRIFTED, Object: object})
+			}
+
+			var resolved []string
+
+			for key := range previous {
+				if _, ok := current[key]; !ok {
+					resolved = append(resolved, key)
+				}
+			}
+
+			sort.Strings(resolved)
+
+			for _, key := range resolved {
+				object := *previous[key]
+				object.Status = DRIFT_EVENT_RESOLVED
+				object.Fields = nil
+
+				emit(&DriftEvent{Event: DRIFT_EVENT_RESOLVED, Object: &object})
+			}
+
+			count := len(drifted)
+			emit(&DriftEvent{Event: DRIFT_EVENT_CHECK, Drifted: &count})
+
+			previous = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *DriftCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "drift <cluster>",
+		Short: "Detect changes made to the cluster outside of sym.yaml",
+		Long:  `Compares the objects rendered from sym.yaml with the live cluster and reports every object that is missing or has drifted, with the drifted fields. With --interval it keeps checking and writes a JSON event per line for alerting.`,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().Duration("interval", 0, "Keep checking at this interval and write JSON events, e.g. 5m")
+
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *DriftCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
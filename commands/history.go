@@ -0,0 +1,72 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type HistoryCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *HistoryCommand) Execute(command *cobra.Command, args []string) error {
+	projectConfig, err := loadClusterProject(command, args, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	history, err := projectConfig.History()
+
+	if err != nil {
+		return err
+	}
+
+	var data [][]interface{}
+
+	for _, record := range history {
+		gitSHA := record.GitSHA
+
+		if len(gitSHA) > 7 {
+			gitSHA = gitSHA[:7]
+		}
+
+		data = append(data, []interface{}{record.ID, record.Time.Local().Format(time.RFC3339), record.Action, record.User, gitSHA, record.Environment, record.ReleaseList()})
+	}
+
+	return output.NewOutput(output.TableOutput{
+		Headers: []string{"ID", "Time", "Action", "User", "Git SHA", "Environment", "Releases"},
+		Data:    data,
+	},
+		history,
+	).VariableOutput()
+}
+
+func (c *HistoryCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "history <cluster>",
+		Short: "List the recorded applies of sym.yaml in a cluster",
+		Long:  `Lists every recorded sym apply and sym rollback with its time, user, git commit and the revision of each Helm release, newest first. The IDs can be passed to sym rollback.`,
+		RunE:  c.Execute,
+	}
+
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *HistoryCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
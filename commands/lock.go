@@ -0,0 +1,97 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type LockCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *LockCommand) Execute(command *cobra.Command, args []string) error {
+	file, err := command.Flags().GetString("file")
+
+	if err != nil {
+		return err
+	}
+
+	projectConfig, err := project.NewProjectConfig(file, c.CommandOpts, c.Client, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	err = projectConfig.Parse()
+
+	if err != nil {
+		return err
+	}
+
+	lockfile, err := projectConfig.Lock()
+
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(lockfile.Charts))
+
+	for name := range lockfile.Charts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var data [][]interface{}
+
+	for _, name := range names {
+		chart := lockfile.Charts[name]
+		data = append(data, []interface{}{name, chart.Chart, chart.Constraint, chart.Version, chart.Digest})
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"Deployment", "Chart", "Constraint", "Version", "Digest"},
+		Data:    data,
+	},
+		lockfile,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Written %s", builder.LOCKFILE_NAME)
+
+	return nil
+}
+
+func (c *LockCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "lock [--file sym.yaml]",
+		Short: "Resolve all remote Helm charts to an exact version and digest and write them to sym.lock",
+		Long:  `Once sym.lock exists, sym apply and sym run only install the locked chart versions and fail when a chart digest changed.`,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().String("file", "sym.yaml", "File to use (default: sym.yaml)")
+
+	return cmd
+}
+
+func (c *LockCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
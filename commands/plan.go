@@ -0,0 +1,91 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type PlanCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *PlanCommand) Execute(command *cobra.Command, args []string) error {
+	detailedExitCode, err := command.Flags().GetBool("detailed-exitcode")
+
+	if err != nil {
+		return err
+	}
+
+	projectConfig, err := loadClusterProject(command, args, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	changes, err := projectConfig.Plan()
+
+	if err != nil {
+		return err
+	}
+
+	var data [][]interface{}
+
+	for _, change := range changes {
+		data = append(data, []interface{}{change.Source, change.Kind, change.Name, change.Namespace, change.Action, strings.Join(change.Fields, ", ")})
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"Source", "Kind", "Name", "Namespace", "Action", "Fields"},
+		Data:    data,
+	},
+		changes,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		c.CommandOpts.Logger.Info().Msg("No changes, the cluster matches sym.yaml.")
+		return nil
+	}
+
+	if detailedExitCode {
+		return &symcommand.ExitError{Code: 2, Message: fmt.Sprintf("Plan has %d change(s)", len(changes))}
+	}
+
+	return nil
+}
+
+func (c *PlanCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "plan <cluster>",
+		Short: "Show what sym apply would change in a cluster",
+		Long: `Renders all Helm releases and kustomizations and compares them with the live objects of the cluster.
+With --detailed-exitcode it exits with 0 when there are no changes, 2 when there are changes and 1 on errors.`,
+		RunE: c.Execute,
+	}
+
+	cmd.Flags().Bool("detailed-exitcode", false, "Exit with code 2 when there are changes")
+
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *PlanCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/commands/preview"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type PreviewCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+var previewCommands []symcommand.Command
+
+func (c *PreviewCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Manage preview environments defined in sym.yaml",
+		Long:  ``,
+		PersistentPreRunE: func(command *cobra.Command, args []string) error {
+			err := symcommand.Initialise(previewCommands, command)
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+		Run: func(command *cobra.Command, args []string) {
+			fmt.Println("Available commands: [up, down, list]")
+		},
+	}
+
+	previewCommands = []symcommand.Command{
+		&preview.UpPreviewCommand{},
+		&preview.DownPreviewCommand{},
+		&preview.ListPreviewCommand{},
+	}
+
+	for _, c := range previewCommands {
+		cmd.AddCommand(c.Command())
+	}
+
+	return cmd
+}
+
+func (c *PreviewCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,248 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package preview
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/identity"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/cli/pkg/util"
+	"github.com/symbiosis-cloud/symbiosis-go"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	pullRequest string
+)
+
+const clusterPageSize = 100
+
+type previewEnvironment struct {
+	Name      string     `json:"name"`
+	Cluster   string     `json:"cluster"`
+	Namespace string     `json:"namespace"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Expired   bool       `json:"expired"`
+}
+
+func newPreviewEnvironment(preview *project.Preview, name string, cluster string, namespace string, createdAt time.Time) *previewEnvironment {
+	env := &previewEnvironment{
+		Name:      name,
+		Cluster:   cluster,
+		Namespace: namespace,
+		CreatedAt: createdAt,
+		Expired:   preview.IsExpired(createdAt),
+	}
+
+	if expiresAt, ok := preview.ExpiresAt(createdAt); ok {
+		env.ExpiresAt = &expiresAt
+	}
+
+	return env
+}
+
+func (e *previewEnvironment) row() []interface{} {
+	expires := ""
+
+	if e.ExpiresAt != nil {
+		expires = e.ExpiresAt.Format(time.RFC3339)
+	}
+
+	return []interface{}{e.Name, e.Cluster, e.Namespace, e.CreatedAt.Format(time.RFC3339), expires, e.Expired}
+}
+
+var previewHeaders = []string{"Name", "Cluster", "Namespace", "Created", "Expires", "Expired"}
+
+func loadProjectConfig(command *cobra.Command, client *symbiosis.Client, opts *symcommand.CommandOpts) (*project.ProjectConfig, *symcommand.DeploymentFlags, error) {
+	deploymentFlags, err := symcommand.GetDeploymentFlags(command)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = project.ApplyEnvironment(deploymentFlags)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts.Environment = deploymentFlags.Environment
+
+	projectConfig, err := project.NewProjectConfig(deploymentFlags.File, opts, client, nil)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = projectConfig.Parse()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if projectConfig.Preview == nil {
+		return nil, nil, fmt.Errorf("No preview section configured in %s", deploymentFlags.File)
+	}
+
+	return projectConfig, deploymentFlags, nil
+}
+
+// previewIdentifier returns the branch or PR the preview belongs to, falling back to the current git branch
+func previewIdentifier(args []string) (string, error) {
+	if pullRequest != "" {
+		return fmt.Sprintf("pr-%s", pullRequest), nil
+	}
+
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	dir, err := os.Getwd()
+
+	if err != nil {
+		return "", err
+	}
+
+	branch, err := util.GitBranch(dir)
+
+	if err != nil {
+		return "", fmt.Errorf("Please provide a branch or --pr identifier, could not detect the current git branch: %v", err)
+	}
+
+	return branch, nil
+}
+
+func sharedClusterClient(client *symbiosis.Client, preview *project.Preview, outputPath string) (*identity.ClusterIdentity, *kubernetes.Clientset, error) {
+	identity, err := identity.NewClusterIdentity(client, preview.Cluster, outputPath, false)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientset, err := util.GetKubernetesClient(identity.KubeConfigPath)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return identity, clientset, nil
+}
+
+// listPreviews returns the previews of the project. Clusters only count as previews when they carry the node
+// pool labels set by preview up, so unrelated clusters sharing the prefix are never touched.
+func listPreviews(client *symbiosis.Client, projectConfig *project.ProjectConfig, outputPath string) ([]*previewEnvironment, error) {
+	var previews []*previewEnvironment
+
+	preview := projectConfig.Preview
+	projectLabel := builder.LabelValue(projectConfig.Project.Name)
+
+	if preview.Strategy == project.PREVIEW_STRATEGY_NAMESPACE {
+		_, clientset, err := sharedClusterClient(client, preview, outputPath)
+
+		if err != nil {
+			return nil, err
+		}
+
+		namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{
+			LabelSelector: project.PREVIEW_LABEL,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, namespace := range namespaces.Items {
+			if !isPreviewNamespace(&namespace, projectLabel) {
+				continue
+			}
+
+			previews = append(previews, newPreviewEnvironment(preview, namespace.Name, preview.Cluster, namespace.Name, namespace.CreationTimestamp.Time))
+		}
+
+		return previews, nil
+	}
+
+	clusters, err := listClusters(client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range clusters {
+		if !preview.IsPreview(cluster.Name) {
+			continue
+		}
+
+		nodePools := cluster.NodePools
+
+		if len(nodePools) == 0 {
+			described, err := client.Cluster.Describe(cluster.Name)
+
+			if err != nil {
+				return nil, err
+			}
+
+			nodePools = described.NodePools
+		}
+
+		if isPreviewCluster(cluster.Name, nodePools, projectLabel) {
+			previews = append(previews, newPreviewEnvironment(preview, cluster.Name, cluster.Name, "", cluster.CreatedAt))
+		}
+	}
+
+	return previews, nil
+}
+
+// isPreviewNamespace reports whether the namespace carries its own preview label and the project label
+func isPreviewNamespace(namespace *v1.Namespace, projectLabel string) bool {
+	return namespace.Labels[project.PREVIEW_LABEL] == namespace.Name && namespace.Labels[builder.PROJECT_LABEL] == projectLabel
+}
+
+// isPreviewCluster reports whether a node pool carries the preview label of the cluster and the project label
+func isPreviewCluster(name string, nodePools []*symbiosis.NodePool, projectLabel string) bool {
+	for _, nodePool := range nodePools {
+		labels := map[string]string{}
+
+		for _, label := range nodePool.Labels {
+			if label != nil {
+				labels[label.Key] = label.Value
+			}
+		}
+
+		if labels[project.PREVIEW_LABEL] == name && labels[builder.PROJECT_LABEL] == projectLabel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listClusters returns every cluster of the team, page by page
+func listClusters(client *symbiosis.Client) ([]*symbiosis.Cluster, error) {
+	var clusters []*symbiosis.Cluster
+
+	for page := 0; ; page++ {
+		list, err := client.Cluster.List(clusterPageSize, page)
+
+		if err != nil {
+			return nil, err
+		}
+
+		clusters = append(clusters, list.Clusters...)
+
+		if len(list.Clusters) < clusterPageSize || list.SortAndPageable == nil || list.Last {
+			return clusters, nil
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package preview
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DownPreviewCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+var (
+	expired bool
+)
+
+func (c *DownPreviewCommand) Execute(command *cobra.Command, args []string) error {
+	projectConfig, deploymentFlags, err := loadProjectConfig(command, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	preview := projectConfig.Preview
+
+	var names []string
+
+	if expired {
+		previews, err := listPreviews(c.Client, projectConfig, deploymentFlags.IdentityOutputPath)
+
+		if err != nil {
+			return err
+		}
+
+		for _, p := range previews {
+			if p.Expired {
+				names = append(names, p.Name)
+			}
+		}
+
+		if len(names) == 0 {
+			c.CommandOpts.Logger.Info().Msg("No expired previews found")
+			return nil
+		}
+	} else {
+		previewId, err := previewIdentifier(args)
+
+		if err != nil {
+			return err
+		}
+
+		name, err := preview.Name(previewId)
+
+		if err != nil {
+			return err
+		}
+
+		names = []string{name}
+	}
+
+	err = output.Confirmation(fmt.Sprintf("Are you sure you want to delete preview %s", strings.Join(names, ", ")), c.CommandOpts.Yes)
+
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		err = c.teardown(projectConfig, name, deploymentFlags.IdentityOutputPath)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// teardown deletes the preview cluster or namespace, after verifying it was created by preview up for this project
+func (c *DownPreviewCommand) teardown(projectConfig *project.ProjectConfig, name string, outputPath string) error {
+	preview := projectConfig.Preview
+	projectLabel := builder.LabelValue(projectConfig.Project.Name)
+
+	if preview.Strategy == project.PREVIEW_STRATEGY_NAMESPACE {
+		_, clientset, err := sharedClusterClient(c.Client, preview, outputPath)
+
+		if err != nil {
+			return err
+		}
+
+		namespace, err := clientset.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+
+		if err != nil {
+			return err
+		}
+
+		if !isPreviewNamespace(namespace, projectLabel) {
+			return fmt.Errorf("Namespace %s in cluster %s is not a preview of project %s, refusing to delete it", name, preview.Cluster, projectConfig.Project.Name)
+		}
+
+		c.CommandOpts.Logger.Info().Msgf("Deleting preview namespace %s in cluster %s", name, preview.Cluster)
+
+		return clientset.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	}
+
+	cluster, err := c.Client.Cluster.Describe(name)
+
+	if err != nil {
+		return err
+	}
+
+	if !isPreviewCluster(name, cluster.NodePools, projectLabel) {
+		return fmt.Errorf("Cluster %s is not a preview of project %s, refusing to delete it", name, projectConfig.Project.Name)
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Deleting preview cluster %s", name)
+
+	return c.Client.Cluster.Delete(name)
+}
+
+func (c *DownPreviewCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "down [branch]",
+		Short: "Tear down the preview environment for a branch or pull request",
+		Long:  ``,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().StringVar(&pullRequest, "pr", "", "Pull request number to derive the preview name from")
+	cmd.Flags().BoolVar(&expired, "expired", false, "Tear down every preview that outlived its ttl")
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *DownPreviewCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package preview
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type ListPreviewCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *ListPreviewCommand) Execute(command *cobra.Command, args []string) error {
+	projectConfig, deploymentFlags, err := loadProjectConfig(command, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	previews, err := listPreviews(c.Client, projectConfig, deploymentFlags.IdentityOutputPath)
+
+	if err != nil {
+		return err
+	}
+
+	var data [][]interface{}
+
+	for _, preview := range previews {
+		data = append(data, preview.row())
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: previewHeaders,
+		Data:    data,
+	},
+		previews,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *ListPreviewCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List preview environments of this project",
+		Long:  ``,
+		RunE:  c.Execute,
+	}
+
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *ListPreviewCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,229 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package preview
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/identity"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/cli/pkg/util"
+	"github.com/symbiosis-cloud/symbiosis-go"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type UpPreviewCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *UpPreviewCommand) Execute(command *cobra.Command, args []string) error {
+	projectConfig, deploymentFlags, err := loadProjectConfig(command, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	preview := projectConfig.Preview
+
+	previewId, err := previewIdentifier(args)
+
+	if err != nil {
+		return err
+	}
+
+	name, err := preview.Name(previewId)
+
+	if err != nil {
+		return err
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Bringing up preview %s for %s", name, previewId)
+
+	var env *previewEnvironment
+
+	if preview.Strategy == project.PREVIEW_STRATEGY_NAMESPACE {
+		// every preview gets its own namespace, deployments with a fixed namespace would be shared between previews
+		if projectConfig.HasNamespaceOverrides() {
+			return fmt.Errorf("Deployments with a namespace are not supported by the namespace preview strategy")
+		}
+
+		env, err = c.ensureNamespace(preview, name, previewId, projectConfig.Project.Name, deploymentFlags.IdentityOutputPath)
+	} else {
+		env, err = c.ensureCluster(preview, name, projectConfig.Project.Name, deploymentFlags.IdentityOutputPath)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if env.Namespace == "" {
+		env.Namespace = deploymentFlags.Namespace
+	}
+
+	c.CommandOpts.Namespace = env.Namespace
+	c.CommandOpts.Cluster = env.Cluster
+
+	err = projectConfig.RunBuilders()
+
+	if err != nil {
+		return err
+	}
+
+	identity, err := identity.NewClusterIdentity(c.Client, env.Cluster, deploymentFlags.IdentityOutputPath, false)
+
+	if err != nil {
+		return err
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Written identity to %s", identity.KubeConfigPath)
+
+	projectConfig.SetIdentity(identity)
+
+	err = projectConfig.RunDeploy()
+
+	if err != nil {
+		return err
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Preview %s is up.", name)
+
+	return output.NewOutput(output.TableOutput{
+		Headers: previewHeaders,
+		Data:    [][]interface{}{env.row()},
+	},
+		env,
+	).VariableOutput()
+}
+
+func (c *UpPreviewCommand) ensureCluster(preview *project.Preview, name string, projectName string, outputPath string) (*previewEnvironment, error) {
+	cluster, err := c.Client.Cluster.Describe(name)
+
+	if err == nil {
+		c.CommandOpts.Logger.Info().Msgf("Using existing preview cluster: %s", name)
+		return newPreviewEnvironment(preview, name, name, "", cluster.CreatedAt), nil
+	} else if !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Creating preview cluster: %s", name)
+
+	cluster, err = c.Client.Cluster.Create(&symbiosis.ClusterInput{
+		Name: name,
+		Nodes: []symbiosis.ClusterNodePoolInput{{
+			Name:         fmt.Sprintf("%s-pool", name),
+			NodeTypeName: preview.NodeType,
+			Quantity:     preview.Nodes,
+			Labels: []symbiosis.NodeLabel{{
+				Key:   "managed-by",
+				Value: "sym-cli",
+			}, {
+				Key:   project.PREVIEW_LABEL,
+				Value: name,
+			}, {
+				Key:   builder.PROJECT_LABEL,
+				Value: builder.LabelValue(projectName),
+			}},
+			Taints: []symbiosis.NodeTaint{},
+		}},
+		IsHighlyAvailable: false,
+		Region:            preview.Region,
+		KubeVersion:       "latest",
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.CommandOpts.Logger.Info().Msg("Cluster created, waiting for node pools to become active...")
+
+	identity, err := identity.NewClusterIdentity(c.Client, name, outputPath, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := util.GetKubernetesClient(identity.KubeConfigPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.WaitForReadyNodes(clientset, 60, time.Second*10)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newPreviewEnvironment(preview, name, name, "", cluster.CreatedAt), nil
+}
+
+func (c *UpPreviewCommand) ensureNamespace(preview *project.Preview, name string, previewId string, projectName string, outputPath string) (*previewEnvironment, error) {
+	_, clientset, err := sharedClusterClient(c.Client, preview, outputPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := clientset.CoreV1().Namespaces()
+
+	namespace, err := namespaces.Get(context.TODO(), name, metav1.GetOptions{})
+
+	if err != nil && errors.IsNotFound(err) {
+		c.CommandOpts.Logger.Info().Msgf("Creating preview namespace %s in cluster %s", name, preview.Cluster)
+
+		namespace, err = namespaces.Create(context.TODO(), &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "sym-cli",
+					project.PREVIEW_LABEL:          name,
+					builder.PROJECT_LABEL:          builder.LabelValue(projectName),
+				},
+				Annotations: map[string]string{
+					project.PREVIEW_IDENTIFIER_ANNOTATION: previewId,
+				},
+			},
+		}, metav1.CreateOptions{})
+	} else if err == nil {
+		c.CommandOpts.Logger.Info().Msgf("Using existing preview namespace %s in cluster %s", name, preview.Cluster)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newPreviewEnvironment(preview, name, preview.Cluster, name, namespace.CreationTimestamp.Time), nil
+}
+
+func (c *UpPreviewCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "up [branch]",
+		Short: "Create (if missing) and deploy the preview environment for a branch or pull request",
+		Long:  ``,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().StringVar(&pullRequest, "pr", "", "Pull request number to derive the preview name from")
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *UpPreviewCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
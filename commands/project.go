@@ -0,0 +1,73 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/identity"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+// loadClusterProject parses sym.yaml for the cluster given as first argument or by the selected environment
+// and sets up the identity of that cluster. The kubeconfig is merged into the local one for commands with --merge.
+func loadClusterProject(command *cobra.Command, args []string, client *symbiosis.Client, opts *symcommand.CommandOpts) (*project.ProjectConfig, error) {
+	deploymentFlags, err := symcommand.GetDeploymentFlags(command)
+
+	if err != nil {
+		return nil, err
+	}
+
+	environment, err := project.ApplyEnvironment(deploymentFlags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var clusterName string
+
+	if len(args) > 0 {
+		clusterName = args[0]
+	} else if environment != nil && environment.Cluster != "" {
+		clusterName = environment.Cluster
+	} else {
+		return nil, fmt.Errorf("Please provide a cluster name (sym %s <cluster>)", command.Name())
+	}
+
+	_, err = client.Cluster.Describe(clusterName)
+
+	if err != nil {
+		return nil, fmt.Errorf("Cluster %s does not exist", clusterName)
+	}
+
+	opts.Namespace = deploymentFlags.Namespace
+	opts.Cluster = clusterName
+	opts.Environment = deploymentFlags.Environment
+
+	identity, err := identity.NewClusterIdentity(client, clusterName, deploymentFlags.IdentityOutputPath, merge)
+
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Logger.Debug().Msgf("Written identity to %s", identity.KubeConfigPath)
+
+	projectConfig, err := project.NewProjectConfig(deploymentFlags.File, opts, client, identity)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = projectConfig.Parse()
+
+	if err != nil {
+		projectConfig.Close()
+		return nil, err
+	}
+
+	return projectConfig, nil
+}
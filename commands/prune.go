@@ -0,0 +1,99 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type PruneCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *PruneCommand) Execute(command *cobra.Command, args []string) error {
+	projectConfig, err := loadClusterProject(command, args, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	return pruneReleases(projectConfig, c.CommandOpts)
+}
+
+// pruneReleases uninstalls the releases of the project that are no longer in sym.yaml after confirmation
+func pruneReleases(projectConfig *project.ProjectConfig, opts *symcommand.CommandOpts) error {
+	releases, err := projectConfig.UndeclaredReleases()
+
+	if err != nil {
+		return err
+	}
+
+	if len(releases) == 0 {
+		opts.Logger.Info().Msg("No releases to prune")
+		return nil
+	}
+
+	names := make([]string, len(releases))
+
+	for i, rel := range releases {
+		names[i] = fmt.Sprintf("%s (namespace %s)", rel.Name, rel.Namespace)
+	}
+
+	err = output.Confirmation(fmt.Sprintf("The following releases are no longer in sym.yaml and will be uninstalled: %s. Continue", strings.Join(names, ", ")), opts.Yes)
+
+	if err != nil {
+		return err
+	}
+
+	results, pruneErr := projectConfig.Prune(releases)
+
+	var data [][]interface{}
+
+	for _, result := range results {
+		data = append(data, []interface{}{result.Kind, result.Name, result.Namespace, result.Status})
+	}
+
+	// report what was removed before the error so a partial prune is visible
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"Kind", "Name", "Namespace", "Status"},
+		Data:    data,
+	},
+		results,
+	).VariableOutput()
+
+	if pruneErr != nil {
+		return pruneErr
+	}
+
+	return err
+}
+
+func (c *PruneCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "prune <cluster>",
+		Short: "Uninstall Helm releases that were removed from sym.yaml",
+		Long:  `Finds the Helm releases sym installed for the project with the same environment and --namespace that are no longer declared in sym.yaml and uninstalls them after confirmation. Releases of other environments and previews are left alone.`,
+		RunE:  c.Execute,
+	}
+
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *PruneCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type RenderCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *RenderCommand) Execute(command *cobra.Command, args []string) error {
+	file, err := command.Flags().GetString("file")
+
+	if err != nil {
+		return err
+	}
+
+	environment, err := command.Flags().GetString("env")
+
+	if err != nil {
+		return err
+	}
+
+	c.CommandOpts.Environment = environment
+
+	projectConfig, err := project.NewProjectConfig(file, c.CommandOpts, c.Client, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	rendered, err := projectConfig.Render(true)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(rendered))
+
+	return nil
+}
+
+func (c *RenderCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "render [--file sym.yaml]",
+		Short: "Print sym.yaml with all template functions resolved and secret values redacted",
+		Long:  ``,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().String("file", "sym.yaml", "File to use (default: sym.yaml)")
+	cmd.Flags().String("env", "", "Environment from the environments section of sym.yaml to use")
+
+	return cmd
+}
+
+func (c *RenderCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type RollbackCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *RollbackCommand) Execute(command *cobra.Command, args []string) error {
+	if len(args) > 2 {
+		return fmt.Errorf("Too many arguments (sym rollback <cluster> [apply-id])")
+	}
+
+	projectConfig, err := loadClusterProject(command, args, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	var id string
+
+	if len(args) > 1 {
+		id = args[1]
+	}
+
+	prompt := fmt.Sprintf("Are you sure you want to roll back the last apply in cluster %s", c.CommandOpts.Cluster)
+
+	if id != "" {
+		prompt = fmt.Sprintf("Are you sure you want to roll back cluster %s to apply %s", c.CommandOpts.Cluster, id)
+	}
+
+	err = output.Confirmation(prompt, c.CommandOpts.Yes)
+
+	if err != nil {
+		return err
+	}
+
+	record, err := projectConfig.Rollback(id)
+
+	if err != nil {
+		return err
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Rolled back to apply %s", record.ID)
+
+	return nil
+}
+
+func (c *RollbackCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "rollback <cluster> [apply-id]",
+		Short: "Restore every Helm release to the revisions of an earlier apply",
+		Long:  `Rolls every Helm release back to the revision recorded by an apply from sym history. Without an apply ID the last apply is undone.`,
+		RunE:  c.Execute,
+	}
+
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *RollbackCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -4,6 +4,7 @@ Copyright © 2022 Symbiosis Cloud
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -52,7 +53,12 @@ var RootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := RootCmd.Execute()
-	if err != nil {
+
+	var exitErr *symcommand.ExitError
+
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.Code)
+	} else if err != nil {
 		os.Exit(1)
 	}
 }
@@ -70,6 +76,15 @@ func init() {
 	betaCommands := []symcommand.Command{
 		&RunCommand{},
 		&ApplyCommand{},
+		&PreviewCommand{},
+		&LockCommand{},
+		&DestroyCommand{},
+		&PlanCommand{},
+		&StatusCommand{},
+		&HistoryCommand{},
+		&RollbackCommand{},
+		&PruneCommand{},
+		&DriftCommand{},
 	}
 
 	commands = []symcommand.Command{
@@ -81,6 +96,10 @@ func init() {
 		&VersionCommand{},
 		&TestCommand{},
 		&CompletionCommand{},
+		&ValidateCommand{},
+		&RenderCommand{},
+		&SecretCommand{},
+		&DoctorCommand{},
 	}
 
 	// TODO: find a way to toggle beta commands via a flag
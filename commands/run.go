@@ -4,108 +4,93 @@ Copyright © 2022 Symbiosis
 package commands
 
 import (
-	"context"
 	"fmt"
-	"strings"
-	"time"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"github.com/symbiosis-cloud/cli/pkg/identity"
+	"github.com/symbiosis-cloud/cli/commands/run"
 	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/state"
 	"github.com/symbiosis-cloud/cli/pkg/symcommand"
 	"github.com/symbiosis-cloud/cli/pkg/util"
 	"github.com/symbiosis-cloud/symbiosis-go"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// TODO: WRITE STATE
-type RunState struct {
-	ID      string `json:"id"`
-	Cluster string `json:"cluster"`
-	State   string `json:"state"`
-}
-
 type RunCommand struct {
 	Client      *symbiosis.Client
 	CommandOpts *symcommand.CommandOpts
 }
 
 var (
-	merge bool
+	merge       bool
+	runCommands []symcommand.Command
 )
 
 func (c *RunCommand) Execute(command *cobra.Command, args []string) error {
 
 	deploymentFlags, err := symcommand.GetDeploymentFlags(command)
 
-	clusterName, err := command.Flags().GetString("cluster-name")
-
 	if err != nil {
 		return err
 	}
 
-	region, err := command.Flags().GetString("region")
+	clusterName, err := command.Flags().GetString("cluster-name")
 
 	if err != nil {
 		return err
 	}
 
-	_, err = c.Client.Cluster.Describe(clusterName)
+	environment, err := project.ApplyEnvironment(deploymentFlags)
 
-	// cluster does not exist, create it
-	if err != nil && strings.Contains(err.Error(), "not found") {
-		c.CreateCluster(clusterName, region, deploymentFlags.IdentityOutputPath)
-	} else if err != nil {
+	if err != nil {
 		return err
-	} else {
-		c.CommandOpts.Logger.Info().Msgf("Using existing cluster: %s", clusterName)
 	}
 
-	c.CommandOpts.Namespace = deploymentFlags.Namespace
+	if environment != nil && environment.Cluster != "" && !command.Flags().Changed("cluster-name") {
+		clusterName = environment.Cluster
+	}
 
-	projectConfig, err := project.NewProjectConfig(deploymentFlags.File, c.CommandOpts, c.Client, nil)
+	overrides, err := clusterOverrides(command)
 
 	if err != nil {
 		return err
 	}
 
-	err = projectConfig.Parse()
+	file, err := filepath.Abs(deploymentFlags.File)
 
 	if err != nil {
 		return err
 	}
 
-	err = projectConfig.RunBuilders()
+	dir, err := state.RunStateDir()
 
 	if err != nil {
 		return err
 	}
 
-	identity, err := identity.NewClusterIdentity(c.Client, clusterName, deploymentFlags.IdentityOutputPath, merge)
+	runState, err := state.NewRunState(dir, util.RandomString(8), clusterName, file, deploymentFlags.Namespace, *overrides)
 
 	if err != nil {
 		return err
 	}
 
-	c.CommandOpts.Logger.Info().Msgf("Written identity to %s", identity.KubeConfigPath)
-
-	projectConfig.SetIdentity(identity)
+	runState.Environment = deploymentFlags.Environment
 
-	// run helm
-	if projectConfig.Deploy != nil && projectConfig.Deploy.Helm != nil {
-		c.CommandOpts.Logger.Info().Msg("Installing helm chart...")
+	err = runState.Write()
 
-		err = projectConfig.RunDeploy()
-
-		if err != nil {
-			return err
-		}
-		return nil
+	if err != nil {
+		return err
 	}
 
-	c.CommandOpts.Logger.Info().Msg("Run finished.")
+	pipeline := &run.Pipeline{
+		Client:             c.Client,
+		CommandOpts:        c.CommandOpts,
+		State:              runState,
+		IdentityOutputPath: deploymentFlags.IdentityOutputPath,
+		Merge:              merge,
+	}
 
-	return nil
+	return pipeline.Run()
 }
 
 func (c *RunCommand) Command() *cobra.Command {
@@ -115,95 +100,87 @@ func (c *RunCommand) Command() *cobra.Command {
 		Short: "Run steps defined in sym.yaml",
 		Long:  ``,
 		RunE:  c.Execute,
+		PersistentPreRunE: func(command *cobra.Command, args []string) error {
+			err := symcommand.Initialise(append(runCommands, c), command)
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
 	}
 
-	cmd.Flags().String("region", "germany-1", "Set the Symbiosis region")
+	cmd.Flags().String("region", "", "Set the Symbiosis region (overrides cluster.region in sym.yaml)")
+	cmd.Flags().String("kube-version", "", "Kubernetes version of the new cluster (overrides cluster.kubeVersion in sym.yaml)")
+	cmd.Flags().Bool("high-availability", false, "Create a highly available cluster (overrides cluster.highlyAvailable in sym.yaml)")
+	cmd.Flags().String("node-type", "", "Node type for every node pool of the new cluster (overrides cluster.nodePools[].nodeType in sym.yaml)")
+	cmd.Flags().Int("node-count", 0, "Node count for every node pool of the new cluster (overrides cluster.nodePools[].quantity in sym.yaml)")
 	cmd.Flags().String("cluster-name", fmt.Sprintf("run-%s", util.RandomString(8)), "Set the Cluster name of the newly created cluster")
 
 	symcommand.SetDeploymentFlags(cmd)
 
+	runCommands = []symcommand.Command{
+		&run.ListRunCommand{},
+		&run.DescribeRunCommand{},
+		&run.ResumeRunCommand{},
+		&run.DestroyRunCommand{},
+	}
+
+	for _, c := range runCommands {
+		cmd.AddCommand(c.Command())
+	}
+
 	return cmd
 }
 
-func (c *RunCommand) CreateCluster(clusterName string, region string, outputPath string) error {
-	c.CommandOpts.Logger.Info().Msgf("Creating cluster: %s", clusterName)
-
-	// TODO: allow changing node pool size
-	numNodes := 2
-
-	_, err := c.Client.Cluster.Create(&symbiosis.ClusterInput{
-		Name: clusterName,
-		Nodes: []symbiosis.ClusterNodePoolInput{{
-			Name:         fmt.Sprintf("%s-autopool", clusterName),
-			NodeTypeName: "general-1", // TODO: allow changing default node type
-			Quantity:     numNodes,    // TODO: allow setting number of nodes
-			Autoscaling: symbiosis.AutoscalingSettings{
-				Enabled: true,
-				MinSize: 2,
-				MaxSize: 10,
-			}, // TODO: allow changing autoscaling
-			Labels: []symbiosis.NodeLabel{{
-				Key:   "managed-by",
-				Value: "sym-cli",
-			}},
-			Taints: []symbiosis.NodeTaint{},
-		}},
-		IsHighlyAvailable: false,
-		Region:            region,
-		KubeVersion:       "latest", // TODO: allow setting custom kubeVersion
-	})
+func clusterOverrides(command *cobra.Command) (*project.ClusterOverrides, error) {
+	overrides := &project.ClusterOverrides{}
+
+	region, err := command.Flags().GetString("region")
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	c.CommandOpts.Logger.Info().Msg("Cluster created, Creating identity...")
-	c.CommandOpts.Logger.Info().Msg("Cluster created, waiting for node pools to become active...")
-
-	identity, err := identity.NewClusterIdentity(c.Client, clusterName, outputPath, merge)
+	kubeVersion, err := command.Flags().GetString("kube-version")
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	clientset, err := util.GetKubernetesClient(identity.KubeConfigPath)
+	nodeType, err := command.Flags().GetString("node-type")
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	it := 0
-	for {
-
-		readyNodes := 0
+	nodeCount, err := command.Flags().GetInt("node-count")
 
-		nodes, _ := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
 
-		for _, node := range nodes.Items {
-		inner:
-			for _, condition := range node.Status.Conditions {
-				if condition.Type == "Ready" && condition.Status == "True" {
-					readyNodes++
-					break inner
-				}
-			}
-		}
+	if command.Flags().Changed("node-count") && nodeCount < 1 {
+		return nil, fmt.Errorf("Invalid node count %d, at least 1 node is required", nodeCount)
+	}
 
-		if readyNodes > 0 {
-			break
-		}
+	if command.Flags().Changed("high-availability") {
+		highlyAvailable, err := command.Flags().GetBool("high-availability")
 
-		if it >= 60 {
-			return fmt.Errorf("Timout trying to check if new cluster is ready")
+		if err != nil {
+			return nil, err
 		}
 
-		time.Sleep(time.Second * 10)
-		it++
+		overrides.HighlyAvailable = &highlyAvailable
 	}
 
-	c.CommandOpts.Logger.Info().Msg("Cluster ready for use")
+	overrides.Region = region
+	overrides.KubeVersion = kubeVersion
+	overrides.NodeType = nodeType
+	overrides.NodeCount = nodeCount
 
-	return nil
+	return overrides, nil
 }
 
 func (c *RunCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
@@ -0,0 +1,86 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package run
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/state"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type DescribeRunCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *DescribeRunCommand) Execute(command *cobra.Command, args []string) error {
+	dir, err := state.RunStateDir()
+
+	if err != nil {
+		return err
+	}
+
+	run, err := state.LoadRunState(dir, args[0])
+
+	if err != nil {
+		return err
+	}
+
+	var hooks []string
+
+	for _, hook := range run.Hooks {
+		outcome := "ok"
+
+		if hook.Error != "" {
+			outcome = "failed"
+		}
+
+		hooks = append(hooks, fmt.Sprintf("%s %q (%s)", hook.Hook, hook.Command, outcome))
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"ID", "Cluster", "Created cluster", "Project", "File", "Hash", "Stage", "Outcome", "Error", "Hooks", "Created", "Updated"},
+		Data: [][]interface{}{{
+			run.ID, run.Cluster, run.ClusterCreated, run.Project, run.File, run.FileHash, run.Stage, run.Outcome, run.Error,
+			strings.Join(hooks, ", "), run.CreatedAt.Format(time.RFC3339), run.UpdatedAt.Format(time.RFC3339),
+		}},
+	},
+		run,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *DescribeRunCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "describe <id>",
+		Short: "Describe a recorded run",
+		Long:  ``,
+		PreRunE: func(command *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("Please provide a run ID (sym run describe <id>)")
+			}
+			return nil
+		},
+		RunE: c.Execute,
+	}
+
+	return cmd
+}
+
+func (c *DescribeRunCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package run
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/state"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type DestroyRunCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *DestroyRunCommand) Execute(command *cobra.Command, args []string) error {
+	dir, err := state.RunStateDir()
+
+	if err != nil {
+		return err
+	}
+
+	run, err := state.LoadRunState(dir, args[0])
+
+	if err != nil {
+		return err
+	}
+
+	if run.Outcome == state.RUN_OUTCOME_DESTROYED {
+		return fmt.Errorf("Run %s has already been destroyed", run.ID)
+	}
+
+	// never delete clusters that existed before the run
+	if run.ClusterCreated {
+		err = output.Confirmation(fmt.Sprintf("Are you sure you want to delete cluster %s created by run %s", run.Cluster, run.ID), c.CommandOpts.Yes)
+
+		if err != nil {
+			return err
+		}
+
+		c.CommandOpts.Logger.Info().Msgf("Deleting cluster %s", run.Cluster)
+
+		err = c.Client.Cluster.Delete(run.Cluster)
+
+		if err != nil {
+			return err
+		}
+	} else {
+		c.CommandOpts.Logger.Info().Msgf("Cluster %s was not created by run %s, leaving it in place", run.Cluster, run.ID)
+	}
+
+	run.Outcome = state.RUN_OUTCOME_DESTROYED
+
+	return run.Write()
+}
+
+func (c *DestroyRunCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "destroy <id>",
+		Short: "Delete the cluster created by a run",
+		Long:  ``,
+		PreRunE: func(command *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("Please provide a run ID (sym run destroy <id>)")
+			}
+			return nil
+		},
+		RunE: c.Execute,
+	}
+
+	return cmd
+}
+
+func (c *DestroyRunCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
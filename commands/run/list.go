@@ -0,0 +1,69 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package run
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/state"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type ListRunCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *ListRunCommand) Execute(command *cobra.Command, args []string) error {
+	dir, err := state.RunStateDir()
+
+	if err != nil {
+		return err
+	}
+
+	runs, err := state.ListRunStates(dir)
+
+	if err != nil {
+		return err
+	}
+
+	var data [][]interface{}
+
+	for _, run := range runs {
+		data = append(data, []interface{}{run.ID, run.Cluster, run.Project, run.Stage, run.Outcome, run.CreatedAt.Format(time.RFC3339)})
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"ID", "Cluster", "Project", "Stage", "Outcome", "Created"},
+		Data:    data,
+	},
+		runs,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *ListRunCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded runs",
+		Long:  ``,
+		RunE:  c.Execute,
+	}
+
+	return cmd
+}
+
+func (c *ListRunCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,234 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package run
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/symbiosis-cloud/cli/pkg/identity"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/state"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/cli/pkg/util"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+// Pipeline executes the stages of sym run and records its progress in the run state so it can be resumed
+type Pipeline struct {
+	Client             *symbiosis.Client
+	CommandOpts        *symcommand.CommandOpts
+	State              *state.RunState
+	IdentityOutputPath string
+	Merge              bool
+}
+
+func (p *Pipeline) Run() error {
+	err := p.execute()
+
+	if stateErr := p.State.Finish(err); stateErr != nil {
+		p.CommandOpts.Logger.Warn().Msgf("Failed to write run state: %v", stateErr)
+	}
+
+	return err
+}
+
+func (p *Pipeline) execute() (err error) {
+	runState := p.State
+
+	p.CommandOpts.Logger.Info().Msgf("Run %s (stage reached: %s)", runState.ID, runState.Stage)
+
+	if runState.Reached(state.RUN_STAGE_DEPLOY) {
+		p.CommandOpts.Logger.Info().Msg("Run already finished, nothing to do.")
+		return nil
+	}
+
+	p.CommandOpts.Namespace = runState.Namespace
+	p.CommandOpts.Cluster = runState.Cluster
+	p.CommandOpts.Environment = runState.Environment
+
+	hash, err := state.HashFile(runState.File)
+
+	if err != nil {
+		return err
+	}
+
+	if hash != runState.FileHash {
+		p.CommandOpts.Logger.Warn().Msgf("%s changed since run %s started", runState.File, runState.ID)
+		runState.FileHash = hash
+	}
+
+	projectConfig, err := project.NewProjectConfig(runState.File, p.CommandOpts, p.Client, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	projectConfig.SetHookRecorder(func(result *project.HookResult) {
+		if err := runState.RecordHook(result); err != nil {
+			p.CommandOpts.Logger.Warn().Msgf("Failed to write run state: %v", err)
+		}
+	})
+
+	// the builders and the deploy run the onFailure hooks themselves, this covers every other stage
+	defer func() {
+		if err != nil {
+			projectConfig.RunFailureHooks(err)
+		}
+	}()
+
+	runState.Project = projectConfig.Project.Name
+
+	err = projectConfig.Parse()
+
+	if err != nil {
+		return err
+	}
+
+	if !runState.Reached(state.RUN_STAGE_CLUSTER) {
+		_, err := p.Client.Cluster.Describe(runState.Cluster)
+
+		// cluster does not exist, create it
+		if err != nil && strings.Contains(err.Error(), "not found") {
+			err = projectConfig.Cluster.Override(runState.Overrides)
+
+			if err != nil {
+				return err
+			}
+
+			err = p.CreateCluster(runState.Cluster, projectConfig.Cluster)
+
+			if err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		} else if runState.ClusterCreated {
+			// the run created the cluster but did not get to see it become ready
+			p.CommandOpts.Logger.Info().Msgf("Resuming with cluster %s created by this run", runState.Cluster)
+
+			err = p.waitForCluster(runState.Cluster)
+
+			if err != nil {
+				return err
+			}
+		} else {
+			p.CommandOpts.Logger.Info().Msgf("Using existing cluster: %s", runState.Cluster)
+		}
+
+		err = runState.Advance(state.RUN_STAGE_CLUSTER)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// builders keep their output in memory so they always run before deploying
+	err = projectConfig.RunBuilders()
+
+	if err != nil {
+		return err
+	}
+
+	err = runState.Advance(state.RUN_STAGE_BUILD)
+
+	if err != nil {
+		return err
+	}
+
+	identity, err := identity.NewClusterIdentity(p.Client, runState.Cluster, p.IdentityOutputPath, p.Merge)
+
+	if err != nil {
+		return err
+	}
+
+	p.CommandOpts.Logger.Info().Msgf("Written identity to %s", identity.KubeConfigPath)
+
+	projectConfig.SetIdentity(identity)
+
+	// run helm and kustomize
+	if projectConfig.Deploy != nil && (projectConfig.Deploy.Helm != nil || projectConfig.Deploy.Kustomize != nil) {
+		p.CommandOpts.Logger.Info().Msg("Deploying project...")
+
+		err = projectConfig.RunDeploy()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	err = runState.Advance(state.RUN_STAGE_DEPLOY)
+
+	if err != nil {
+		return err
+	}
+
+	p.CommandOpts.Logger.Info().Msg("Run finished.")
+
+	return nil
+}
+
+func (p *Pipeline) CreateCluster(clusterName string, cluster *project.Cluster) error {
+	p.CommandOpts.Logger.Info().Msgf("Creating cluster: %s", clusterName)
+
+	input, err := cluster.ClusterInput(clusterName)
+
+	if err != nil {
+		return err
+	}
+
+	debugPayload, err := json.MarshalIndent(input, "", " ")
+
+	if err != nil {
+		return err
+	}
+
+	p.CommandOpts.Logger.Debug().Msgf("Sending payload: %s", string(debugPayload))
+
+	_, err = p.Client.Cluster.Create(input)
+
+	if err != nil {
+		return err
+	}
+
+	// record the cluster right away so run destroy deletes it even when waiting for it fails
+	p.State.ClusterCreated = true
+
+	err = p.State.Write()
+
+	if err != nil {
+		return err
+	}
+
+	p.CommandOpts.Logger.Info().Msg("Cluster created, waiting for node pools to become active...")
+
+	return p.waitForCluster(clusterName)
+}
+
+func (p *Pipeline) waitForCluster(clusterName string) error {
+	identity, err := identity.NewClusterIdentity(p.Client, clusterName, p.IdentityOutputPath, p.Merge)
+
+	if err != nil {
+		return err
+	}
+
+	clientset, err := util.GetKubernetesClient(identity.KubeConfigPath)
+
+	if err != nil {
+		return err
+	}
+
+	err = util.WaitForReadyNodes(clientset, 60, time.Second*10)
+
+	if err != nil {
+		return err
+	}
+
+	p.CommandOpts.Logger.Info().Msg("Cluster ready for use")
+
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package run
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/state"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type ResumeRunCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+var (
+	identityOutputPath string
+	merge              bool
+)
+
+func (c *ResumeRunCommand) Execute(command *cobra.Command, args []string) error {
+	dir, err := state.RunStateDir()
+
+	if err != nil {
+		return err
+	}
+
+	run, err := state.LoadRunState(dir, args[0])
+
+	if err != nil {
+		return err
+	}
+
+	if run.Outcome == state.RUN_OUTCOME_DESTROYED {
+		return fmt.Errorf("Run %s has been destroyed and cannot be resumed", run.ID)
+	}
+
+	run.Outcome = state.RUN_OUTCOME_RUNNING
+
+	pipeline := &Pipeline{
+		Client:             c.Client,
+		CommandOpts:        c.CommandOpts,
+		State:              run,
+		IdentityOutputPath: identityOutputPath,
+		Merge:              merge,
+	}
+
+	return pipeline.Run()
+}
+
+func (c *ResumeRunCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "resume <id>",
+		Short: "Resume a run from the last stage it reached",
+		Long:  ``,
+		PreRunE: func(command *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("Please provide a run ID (sym run resume <id>)")
+			}
+			return nil
+		},
+		RunE: c.Execute,
+	}
+
+	cmd.Flags().StringVar(&identityOutputPath, "identity-output-path", "", "Write the generated kubeConfig file to this location")
+	cmd.Flags().BoolVar(&merge, "merge", false, "Merge the generated kubeConfig file with the one on your system")
+
+	return cmd
+}
+
+func (c *ResumeRunCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/commands/secret"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type SecretCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+var secretCommands []symcommand.Command
+
+func (c *SecretCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "secret <command>",
+		Short: "Manage project secrets",
+		Long:  ``,
+		PersistentPreRunE: func(command *cobra.Command, args []string) error {
+			err := symcommand.Initialise(secretCommands, command)
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+		Run: func(command *cobra.Command, args []string) {
+			fmt.Println("Available commands: [list, get, set, delete, import, export]")
+		},
+	}
+
+	secretCommands = []symcommand.Command{
+		&secret.ListSecretCommand{},
+		&secret.GetSecretCommand{},
+		&secret.SetSecretCommand{},
+		&secret.DeleteSecretCommand{},
+		&secret.ImportSecretCommand{},
+		&secret.ExportSecretCommand{},
+	}
+
+	for _, c := range secretCommands {
+		cmd.AddCommand(c.Command())
+	}
+
+	return cmd
+}
+
+func (c *SecretCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
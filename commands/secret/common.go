@@ -0,0 +1,119 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/symbiosis-cloud/cli/pkg/secrets"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+const maskedValue = "********"
+
+var (
+	environment  string
+	environments []string
+	reveal       bool
+)
+
+// secretsManager scopes secret operations to the project selected with --project or stored in .symbiosis.project
+func secretsManager(client *symbiosis.Client, opts *symcommand.CommandOpts) (*secrets.SecretsManager, string, error) {
+	project := opts.Project
+
+	if project == nil {
+		dir, err := os.Getwd()
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		projectFile, err := os.ReadFile(path.Join(dir, ".symbiosis.project"))
+
+		if err != nil && os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("Please select a project with --project")
+		} else if err != nil {
+			return nil, "", err
+		}
+
+		err = json.Unmarshal(projectFile, &project)
+
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	var projectEnvironment symbiosis.ProjectEnvironment
+
+	if environment != "" {
+		e, err := secrets.ParseEnvironment(environment)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		projectEnvironment = e
+	}
+
+	return secrets.NewSecretManager(client, project.Name, projectEnvironment), project.Name, nil
+}
+
+// secretEnvironments parses the repeatable --env flag of set and import. Without the flag nil is returned, so
+// existing secrets keep their environments and new ones are enabled for all of them.
+func secretEnvironments() ([]symbiosis.ProjectEnvironment, error) {
+	if len(environments) == 0 {
+		return nil, nil
+	}
+
+	projectEnvironments := make([]symbiosis.ProjectEnvironment, len(environments))
+
+	for i, env := range environments {
+		e, err := secrets.ParseEnvironment(env)
+
+		if err != nil {
+			return nil, err
+		}
+
+		projectEnvironments[i] = e
+	}
+
+	return projectEnvironments, nil
+}
+
+func secretRow(key string, secret *symbiosis.Secret, reveal bool) []interface{} {
+	value := maskedValue
+
+	if reveal {
+		value = secret.Value
+	}
+
+	return []interface{}{key, value, secret.IsDevelopmentSecret, secret.IsPreviewSecret, secret.IsProductionSecret}
+}
+
+// redactedSecret returns a copy of a secret without its value for json and yaml output
+func redactedSecret(secret *symbiosis.Secret, reveal bool) *symbiosis.Secret {
+	if reveal || secret == nil {
+		return secret
+	}
+
+	redacted := *secret
+	redacted.Value = maskedValue
+
+	return &redacted
+}
+
+// describeEnvironments returns the environments given with --env for log messages
+func describeEnvironments(projectEnvironments []symbiosis.ProjectEnvironment) string {
+	if len(projectEnvironments) == 0 {
+		return "its current environments (all for new secrets)"
+	}
+
+	return fmt.Sprintf("%v", projectEnvironments)
+}
+
+var secretHeaders = []string{"Key", "Value", "Development", "Preview", "Production"}
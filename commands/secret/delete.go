@@ -0,0 +1,59 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type DeleteSecretCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *DeleteSecretCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "delete <key>",
+		Short: "Delete a project secret",
+		Long:  ``,
+		PreRunE: func(command *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("Please provide a secret key (sym secret delete <key>)")
+			}
+
+			return output.Confirmation(fmt.Sprintf("Are you sure you want to delete secret %s", args[0]), c.CommandOpts.Yes)
+		},
+		RunE: func(command *cobra.Command, args []string) error {
+			manager, project, err := secretsManager(c.Client, c.CommandOpts)
+
+			if err != nil {
+				return err
+			}
+
+			key := args[0]
+			c.CommandOpts.Logger.Info().Msgf("Deleting secret %s from project %s", key, project)
+
+			err = manager.DeleteSecret(key)
+
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func (c *DeleteSecretCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
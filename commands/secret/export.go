@@ -0,0 +1,82 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/secrets"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type ExportSecretCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+var (
+	exportFile string
+)
+
+func (c *ExportSecretCommand) Execute(command *cobra.Command, args []string) error {
+	manager, _, err := secretsManager(c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	collection, err := manager.GetSecrets()
+
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string, len(collection))
+
+	for key, secret := range collection {
+		if secret != nil {
+			values[key] = secret.Value
+		}
+	}
+
+	dotenv := secrets.FormatDotenv(values)
+
+	if exportFile == "" {
+		_, err = os.Stdout.Write(dotenv)
+		return err
+	}
+
+	err = ioutil.WriteFile(exportFile, dotenv, 0600)
+
+	if err != nil {
+		return err
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Exported %d secrets to %s", len(values), exportFile)
+
+	return nil
+}
+
+func (c *ExportSecretCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "export [--file path]",
+		Short: "Export project secrets as a dotenv file",
+		Long:  ``,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().StringVar(&environment, "env", "", "Only export secrets of this environment (development, preview or production)")
+	cmd.Flags().StringVar(&exportFile, "file", "", "Write the dotenv file to this location instead of stdout")
+
+	return cmd
+}
+
+func (c *ExportSecretCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
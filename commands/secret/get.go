@@ -0,0 +1,80 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type GetSecretCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *GetSecretCommand) Execute(command *cobra.Command, args []string) error {
+	manager, _, err := secretsManager(c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	key := args[0]
+
+	secret, err := manager.GetSecret(key)
+
+	if err != nil {
+		return err
+	}
+
+	// print the plain value by default so it can be used in scripts
+	if output.OutputFormat == string(output.OUTPUT_TABLE) && !reveal {
+		fmt.Println(secret.Value)
+		return nil
+	}
+
+	// json and yaml output only contain the value with --reveal
+	err = output.NewOutput(output.TableOutput{
+		Headers: secretHeaders,
+		Data:    [][]interface{}{secretRow(key, secret, true)},
+	},
+		redactedSecret(secret, reveal),
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *GetSecretCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a project secret",
+		Long:  ``,
+		PreRunE: func(command *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("Please provide a secret key (sym secret get <key>)")
+			}
+			return nil
+		},
+		RunE: c.Execute,
+	}
+
+	cmd.Flags().StringVar(&environment, "env", "", "Read the secret from this environment (development, preview or production)")
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "Show the secret as a table including the environments it is enabled for, and its value in json and yaml output")
+
+	return cmd
+}
+
+func (c *GetSecretCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package secret
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/secrets"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type ImportSecretCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *ImportSecretCommand) Execute(command *cobra.Command, args []string) error {
+	manager, project, err := secretsManager(c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	projectEnvironments, err := secretEnvironments()
+
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(args[0])
+
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	values, err := secrets.ParseDotenv(file)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse %s: %v", args[0], err)
+	}
+
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		err = manager.SetSecret(key, values[key], projectEnvironments)
+
+		if err != nil {
+			return fmt.Errorf("Failed to set secret %s: %v", key, err)
+		}
+
+		c.CommandOpts.Logger.Debug().Msgf("Imported secret %s", key)
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Imported %d secrets into project %s for %s", len(keys), project, describeEnvironments(projectEnvironments))
+
+	return nil
+}
+
+func (c *ImportSecretCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "import <file.env>",
+		Short: "Import project secrets from a dotenv file",
+		Long:  ``,
+		PreRunE: func(command *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("Please provide a dotenv file (sym secret import <file.env>)")
+			}
+			return nil
+		},
+		RunE: c.Execute,
+	}
+
+	cmd.Flags().StringSliceVar(&environments, "env", []string{}, "Environments to enable the secrets for (default: all for new secrets, existing secrets keep their environments)")
+
+	return cmd
+}
+
+func (c *ImportSecretCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package secret
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type ListSecretCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *ListSecretCommand) Execute(command *cobra.Command, args []string) error {
+	manager, _, err := secretsManager(c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	secrets, err := manager.GetSecrets()
+
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(secrets))
+
+	for key := range secrets {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var data [][]interface{}
+
+	// json and yaml output are redacted like the table unless --reveal is set
+	redacted := make(symbiosis.SecretCollection, len(secrets))
+
+	for _, key := range keys {
+		data = append(data, secretRow(key, secrets[key], reveal))
+		redacted[key] = redactedSecret(secrets[key], reveal)
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: secretHeaders,
+		Data:    data,
+	},
+		redacted,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *ListSecretCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List project secrets",
+		Long:  ``,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().StringVar(&environment, "env", "", "Only list secrets of this environment (development, preview or production)")
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "Show secret values")
+
+	return cmd
+}
+
+func (c *ListSecretCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
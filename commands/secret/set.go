@@ -0,0 +1,92 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package secret
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type SetSecretCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+var (
+	valueFile string
+)
+
+func (c *SetSecretCommand) Execute(command *cobra.Command, args []string) error {
+	manager, project, err := secretsManager(c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	projectEnvironments, err := secretEnvironments()
+
+	if err != nil {
+		return err
+	}
+
+	key := args[0]
+
+	var value []byte
+
+	if valueFile != "" {
+		value, err = os.ReadFile(valueFile)
+	} else {
+		c.CommandOpts.Logger.Info().Msgf("Reading value for secret %s from stdin", key)
+		value, err = io.ReadAll(os.Stdin)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// values piped through echo end with a newline that is not part of the secret
+	secretValue := strings.TrimSuffix(string(value), "\n")
+
+	err = manager.SetSecret(key, secretValue, projectEnvironments)
+
+	if err != nil {
+		return err
+	}
+
+	c.CommandOpts.Logger.Info().Msgf("Secret %s set in project %s for %s", key, project, describeEnvironments(projectEnvironments))
+
+	return nil
+}
+
+func (c *SetSecretCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "set <key> [--value-file path]",
+		Short: "Create or update a project secret, reading the value from stdin or a file",
+		Long:  ``,
+		PreRunE: func(command *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("Please provide a secret key (sym secret set <key>)")
+			}
+			return nil
+		},
+		RunE: c.Execute,
+	}
+
+	cmd.Flags().StringVar(&valueFile, "value-file", "", "Read the secret value from this file instead of stdin")
+	cmd.Flags().StringSliceVar(&environments, "env", []string{}, "Environments to enable the secret for (default: all for new secrets, existing secrets keep their environments)")
+
+	return cmd
+}
+
+func (c *SetSecretCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
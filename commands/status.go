@@ -0,0 +1,99 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type StatusCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (c *StatusCommand) Execute(command *cobra.Command, args []string) error {
+	projectConfig, err := loadClusterProject(command, args, c.Client, c.CommandOpts)
+
+	if err != nil {
+		return err
+	}
+
+	defer projectConfig.Close()
+
+	statuses, err := projectConfig.Status()
+
+	if err != nil {
+		return err
+	}
+
+	var data [][]interface{}
+	var missing []string
+
+	for _, status := range statuses {
+		if !status.Deployed {
+			missing = append(missing, status.Name)
+			data = append(data, []interface{}{status.Builder, status.Name, status.Namespace, "-", "-", "-", strings.ToUpper(status.Status), "-"})
+			continue
+		}
+
+		revision, chart, lastDeployed := "-", "-", "-"
+
+		if status.Revision > 0 {
+			revision = fmt.Sprintf("%d", status.Revision)
+		}
+
+		if status.Chart != "" {
+			chart = status.Chart
+		}
+
+		if status.LastDeployed != nil {
+			lastDeployed = status.LastDeployed.Format(time.RFC3339)
+		}
+
+		data = append(data, []interface{}{status.Builder, status.Name, status.Namespace, revision, chart, lastDeployed, status.Status, fmt.Sprintf("%d/%d", status.Ready, status.Desired)})
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"Builder", "Name", "Namespace", "Revision", "Chart", "Last deployed", "Status", "Ready"},
+		Data:    data,
+	},
+		statuses,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		c.CommandOpts.Logger.Warn().Msgf("Not deployed: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func (c *StatusCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "status <cluster>",
+		Short: "Show the status of everything sym.yaml manages in a cluster",
+		Long:  `Shows the revision, chart, last deploy, status and ready replicas of every Helm release and kustomization in sym.yaml and flags the ones that are not deployed.`,
+		RunE:  c.Execute,
+	}
+
+	symcommand.SetDeploymentFlags(cmd)
+
+	return cmd
+}
+
+func (c *StatusCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
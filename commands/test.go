@@ -7,8 +7,6 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
-	"github.com/symbiosis-cloud/cli/pkg/identity"
-	"github.com/symbiosis-cloud/cli/pkg/project"
 	"github.com/symbiosis-cloud/cli/pkg/symcommand"
 	"github.com/symbiosis-cloud/symbiosis-go"
 )
@@ -24,41 +22,13 @@ var (
 
 func (c *TestCommand) Execute(command *cobra.Command, args []string) error {
 
-	_, err := c.Client.Cluster.Describe(args[0])
+	projectConfig, err := loadClusterProject(command, args, c.Client, c.CommandOpts)
 
 	if err != nil {
 		return err
 	}
 
-	clusterName := args[0]
-
-	deploymentFlags, err := symcommand.GetDeploymentFlags(command)
-
-	if err != nil {
-		return err
-	}
-
-	c.CommandOpts.Namespace = deploymentFlags.Namespace
-
-	identity, err := identity.NewClusterIdentity(c.Client, clusterName, deploymentFlags.IdentityOutputPath, false)
-
-	if err != nil {
-		return err
-	}
-
-	c.CommandOpts.Logger.Info().Msgf("Written identity to %s", identity.KubeConfigPath)
-
-	projectConfig, err := project.NewProjectConfig(deploymentFlags.File, c.CommandOpts, c.Client, identity)
-
-	if err != nil {
-		return err
-	}
-
-	err = projectConfig.Parse()
-
-	if err != nil {
-		return err
-	}
+	defer projectConfig.Close()
 
 	err = projectConfig.RunTests(testOutputDir)
 
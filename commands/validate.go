@@ -0,0 +1,102 @@
+/*
+Copyright © 2022 Symbiosis
+*/
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/symbiosis-cloud/cli/pkg/output"
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type ValidateCommand struct {
+	Client      *symbiosis.Client
+	CommandOpts *symcommand.CommandOpts
+}
+
+var (
+	printSchema bool
+)
+
+func (c *ValidateCommand) Execute(command *cobra.Command, args []string) error {
+
+	if printSchema {
+		schema, err := json.MarshalIndent(project.Schema(), "", "  ")
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(schema))
+
+		return nil
+	}
+
+	file, err := command.Flags().GetString("file")
+
+	if err != nil {
+		return err
+	}
+
+	validationErrors, err := project.Validate(file)
+
+	if err != nil {
+		return err
+	}
+
+	if len(validationErrors) == 0 {
+		c.CommandOpts.Logger.Info().Msgf("%s is valid", file)
+		return nil
+	}
+
+	var data [][]interface{}
+
+	rendered := false
+
+	for _, e := range validationErrors {
+		data = append(data, []interface{}{e.File, e.Line, e.Column, e.Path, e.Message})
+		rendered = rendered || e.Rendered
+	}
+
+	if rendered {
+		c.CommandOpts.Logger.Warn().Msgf("The template of %s changes its line count, lines refer to the output of sym render", file)
+	}
+
+	err = output.NewOutput(output.TableOutput{
+		Headers: []string{"File", "Line", "Column", "Path", "Error"},
+		Data:    data,
+	},
+		validationErrors,
+	).VariableOutput()
+
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%s is invalid: found %d problem(s)", file, len(validationErrors))
+}
+
+func (c *ValidateCommand) Command() *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:   "validate [--file sym.yaml]",
+		Short: "Validate sym.yaml offline against its schema, local charts and values files",
+		Long:  ``,
+		RunE:  c.Execute,
+	}
+
+	cmd.Flags().String("file", "sym.yaml", "File to use (default: sym.yaml)")
+	cmd.Flags().BoolVar(&printSchema, "print-schema", false, "Print the JSON Schema of sym.yaml instead of validating")
+
+	return cmd
+}
+
+func (c *ValidateCommand) Init(client *symbiosis.Client, opts *symcommand.CommandOpts) {
+	c.Client = client
+	c.CommandOpts = opts
+}
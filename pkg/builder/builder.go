@@ -1,12 +1,29 @@
 package builder
 
-import "github.com/symbiosis-cloud/cli/pkg/identity"
+import (
+	"strings"
+
+	"github.com/symbiosis-cloud/cli/pkg/identity"
+)
 
 type Builder interface {
 	Build() error
 	Deploy() error
+	Destroy() ([]*DestroyResult, error)
+	Render() ([]*Manifest, error)
+	Status() ([]*DeploymentStatus, error)
 	GetIdentity() *identity.ClusterIdentity
 	SetIdentity(identity *identity.ClusterIdentity)
+	Namespaces() []string
 
 	requirements() ([]Requirement, error)
 }
+
+// ExpandPath resolves paths relative to the directory containing sym.yaml
+func ExpandPath(dir string, path string) string {
+	if strings.Contains(path, "./") {
+		return strings.Replace(path, "./", dir+"/", 1)
+	}
+
+	return path
+}
@@ -0,0 +1,120 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretResolver returns the value of a project secret by name
+type SecretResolver func(name string) (string, error)
+
+type helmCredentials struct {
+	username string
+	password string
+	caFile   string
+}
+
+// credentials resolves the secrets referenced by a repository, nil means the repository needs no authentication
+func (b *HelmBuilder) credentials(r *HelmRepository) (*helmCredentials, error) {
+	if r == nil || !r.hasCredentials() {
+		return nil, nil
+	}
+
+	if b.secrets == nil {
+		return nil, fmt.Errorf("Repository credentials require project secrets")
+	}
+
+	credentials := &helmCredentials{
+		username: r.Username,
+	}
+
+	if r.UsernameSecret != "" {
+		username, err := b.secrets(r.UsernameSecret)
+
+		if err != nil {
+			return nil, err
+		}
+
+		credentials.username = username
+	}
+
+	// registries exchange basic credentials for a bearer token, so a token is sent as the password
+	for _, name := range []string{r.PasswordSecret, r.TokenSecret} {
+		if name == "" {
+			continue
+		}
+
+		password, err := b.secrets(name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		credentials.password = password
+	}
+
+	if r.CaFile != "" {
+		credentials.caFile = b.expandPaths(r.CaFile)
+	}
+
+	if r.CaSecret != "" {
+		ca, err := b.secrets(r.CaSecret)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the bundle lives in the Helm home, so it is removed together with it
+		if b.home == "" {
+			return nil, fmt.Errorf("Repository CA bundles require a Helm home")
+		}
+
+		file, err := os.CreateTemp(b.home, "ca-*.pem")
+
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		_, err = file.WriteString(ca)
+
+		if err != nil {
+			return nil, err
+		}
+
+		credentials.caFile = file.Name()
+	}
+
+	return credentials, nil
+}
+
+func (r *HelmRepository) hasCredentials() bool {
+	return r.Username != "" || r.UsernameSecret != "" || r.PasswordSecret != "" || r.TokenSecret != "" || r.CaFile != "" || r.CaSecret != ""
+}
+
+// ValidateCredentials checks that the credential references of a repository are complete
+func (r *HelmRepository) ValidateCredentials() error {
+	if r.PasswordSecret != "" && r.TokenSecret != "" {
+		return fmt.Errorf("Use either passwordSecret or tokenSecret, not both")
+	}
+
+	if r.Username != "" && r.UsernameSecret != "" {
+		return fmt.Errorf("Use either username or usernameSecret, not both")
+	}
+
+	if (r.PasswordSecret != "" || r.TokenSecret != "") && r.Username == "" && r.UsernameSecret == "" {
+		return fmt.Errorf("A username or usernameSecret is required together with a password or token")
+	}
+
+	if r.CaFile != "" && r.CaSecret != "" {
+		return fmt.Errorf("Use either caFile or caSecret, not both")
+	}
+
+	return nil
+}
+
+// registryHost returns the registry of an oci:// chart reference
+func registryHost(chart string) string {
+	return strings.SplitN(strings.TrimPrefix(chart, OCI_PREFIX), "/", 2)[0]
+}
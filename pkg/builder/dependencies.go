@@ -0,0 +1,106 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateHelmDependencies checks dependsOn declarations for unknown deployments and cycles
+func ValidateHelmDependencies(deployments []HelmDeployment) error {
+	_, err := resolveHelmWaves(deployments)
+
+	return err
+}
+
+// resolveHelmWaves orders deployments into waves using their dependsOn declarations. Every
+// deployment in a wave only depends on deployments from earlier waves, so a wave can be
+// installed concurrently once the previous one succeeded.
+func resolveHelmWaves(deployments []HelmDeployment) ([][]HelmDeployment, error) {
+	index := make(map[string]int, len(deployments))
+
+	for i, d := range deployments {
+		if d.Name == "" {
+			return nil, fmt.Errorf("Helm deployment %d has no name", i)
+		}
+
+		if _, ok := index[d.Name]; ok {
+			return nil, fmt.Errorf("Helm deployment %s is declared more than once", d.Name)
+		}
+
+		index[d.Name] = i
+	}
+
+	// number of unresolved dependencies per deployment and the reverse edges of the graph
+	pending := make([]int, len(deployments))
+	dependents := make([][]int, len(deployments))
+
+	for i, d := range deployments {
+		seen := map[string]bool{}
+
+		for _, dependency := range d.DependsOn {
+			j, ok := index[dependency]
+
+			if !ok {
+				return nil, fmt.Errorf("Helm deployment %s depends on unknown deployment %s", d.Name, dependency)
+			}
+
+			if j == i {
+				return nil, fmt.Errorf("Helm deployment %s cannot depend on itself", d.Name)
+			}
+
+			if seen[dependency] {
+				continue
+			}
+
+			seen[dependency] = true
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	var waves [][]HelmDeployment
+	var current []int
+
+	for i := range deployments {
+		if pending[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	resolved := 0
+
+	for len(current) > 0 {
+		wave := make([]HelmDeployment, len(current))
+		var next []int
+
+		for x, i := range current {
+			wave[x] = deployments[i]
+			resolved++
+
+			for _, dependent := range dependents[i] {
+				pending[dependent]--
+
+				if pending[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+
+		waves = append(waves, wave)
+		current = next
+	}
+
+	if resolved != len(deployments) {
+		var cycle []string
+
+		for i, d := range deployments {
+			if pending[i] > 0 {
+				cycle = append(cycle, d.Name)
+			}
+		}
+
+		return nil, fmt.Errorf("Circular dependency detected between Helm deployments: %s", strings.Join(cycle, ", "))
+	}
+
+	return waves, nil
+}
@@ -0,0 +1,105 @@
+package builder
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveHelmWaves(t *testing.T) {
+	deployment := func(name string, dependsOn ...string) HelmDeployment {
+		return HelmDeployment{Name: name, DependsOn: dependsOn}
+	}
+
+	tests := []struct {
+		name        string
+		deployments []HelmDeployment
+		waves       [][]string
+		err         string
+	}{
+		{
+			name:        "no deployments",
+			deployments: nil,
+			waves:       nil,
+		},
+		{
+			name:        "independent deployments share a wave",
+			deployments: []HelmDeployment{deployment("a"), deployment("b")},
+			waves:       [][]string{{"a", "b"}},
+		},
+		{
+			name:        "chain",
+			deployments: []HelmDeployment{deployment("c", "b"), deployment("b", "a"), deployment("a")},
+			waves:       [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			name:        "diamond",
+			deployments: []HelmDeployment{deployment("db"), deployment("api", "db"), deployment("worker", "db"), deployment("web", "api", "worker")},
+			waves:       [][]string{{"db"}, {"api", "worker"}, {"web"}},
+		},
+		{
+			name:        "duplicate dependency",
+			deployments: []HelmDeployment{deployment("a"), deployment("b", "a", "a")},
+			waves:       [][]string{{"a"}, {"b"}},
+		},
+		{
+			name:        "missing name",
+			deployments: []HelmDeployment{deployment("")},
+			err:         "has no name",
+		},
+		{
+			name:        "declared twice",
+			deployments: []HelmDeployment{deployment("a"), deployment("a")},
+			err:         "declared more than once",
+		},
+		{
+			name:        "unknown dependency",
+			deployments: []HelmDeployment{deployment("a", "b")},
+			err:         "unknown deployment b",
+		},
+		{
+			name:        "depends on itself",
+			deployments: []HelmDeployment{deployment("a", "a")},
+			err:         "cannot depend on itself",
+		},
+		{
+			name:        "cycle",
+			deployments: []HelmDeployment{deployment("a"), deployment("b", "c"), deployment("c", "b")},
+			err:         "Circular dependency detected between Helm deployments: b, c",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			waves, err := resolveHelmWaves(test.deployments)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var names [][]string
+
+			for _, wave := range waves {
+				var waveNames []string
+
+				for _, d := range wave {
+					waveNames = append(waveNames, d.Name)
+				}
+
+				names = append(names, waveNames)
+			}
+
+			if !reflect.DeepEqual(names, test.waves) {
+				t.Errorf("expected waves %v, got %v", test.waves, names)
+			}
+		})
+	}
+}
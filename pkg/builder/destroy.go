@@ -0,0 +1,124 @@
+package builder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+)
+
+const (
+	DESTROY_STATUS_REMOVED     = "removed"
+	DESTROY_STATUS_NOT_PRESENT = "not present"
+	DESTROY_STATUS_NOT_MANAGED = "not managed"
+)
+
+// DestroyResult describes a single resource removed by sym destroy
+type DestroyResult struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Status    string `json:"status"`
+}
+
+// Destroy uninstalls all releases, dependents before the releases they depend on. Releases with the same name that
+// were not installed by this project, environment and namespace are left alone.
+func (b *HelmBuilder) Destroy() ([]*DestroyResult, error) {
+	var results []*DestroyResult
+
+	for i := len(b.waves) - 1; i >= 0; i-- {
+		for _, d := range b.waves[i] {
+			namespace := b.namespace(d)
+
+			config, err := b.actionConfig(namespace)
+
+			if err != nil {
+				return results, err
+			}
+
+			status, err := b.destroyRelease(config, d.Name)
+
+			if err != nil {
+				return results, err
+			}
+
+			results = append(results, &DestroyResult{"helm", d.Name, namespace, status})
+		}
+	}
+
+	return results, nil
+}
+
+// destroyRelease uninstalls a release after checking it was deployed by the project of the builder
+func (b *HelmBuilder) destroyRelease(config *action.Configuration, name string) (string, error) {
+	rel, err := config.Releases.Last(name)
+
+	if errors.Is(err, ErrReleaseNotFound) {
+		return DESTROY_STATUS_NOT_PRESENT, nil
+	} else if err != nil {
+		return "", &HelmError{Action: "status", Release: name, Err: err}
+	}
+
+	if !deployedBy(rel.Chart, b.project, b.CommandOpts.Environment, b.CommandOpts.Namespace) {
+		b.CommandOpts.Logger.Warn().Msgf("Skipping Helm release %s, it was not deployed by this project", name)
+		return DESTROY_STATUS_NOT_MANAGED, nil
+	}
+
+	b.CommandOpts.Logger.Info().Msgf("Uninstalling Helm release %s", name)
+
+	err = uninstallRelease(config, name)
+
+	if errors.Is(err, ErrReleaseNotFound) {
+		return DESTROY_STATUS_NOT_PRESENT, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return DESTROY_STATUS_REMOVED, nil
+}
+
+// Destroy deletes the objects of all kustomizations in reverse order
+func (b *KustomizeBuilder) Destroy() ([]*DestroyResult, error) {
+	var results []*DestroyResult
+
+	for i := len(b.deployments) - 1; i >= 0; i-- {
+		d := b.deployments[i]
+
+		manifest, err := b.manifest(d)
+
+		if err != nil {
+			return results, err
+		}
+
+		b.CommandOpts.Logger.Info().Msgf("Deleting kustomization %s", d.Path)
+
+		args := []string{"delete", "--kubeconfig", b.GetIdentity().KubeConfigPath, "--namespace", b.namespace(d), "--ignore-not-found", "-f", "-"}
+
+		b.CommandOpts.Logger.Debug().Msgf("Running kubectl %s", strings.Join(args, " "))
+
+		remove := exec.Command("kubectl", args...)
+		remove.Stdin = bytes.NewReader(manifest)
+
+		output, err := remove.CombinedOutput()
+
+		if err != nil {
+			return results, fmt.Errorf("Kubectl delete failed. Full output: %s", output)
+		}
+
+		b.CommandOpts.Logger.Debug().Msg(string(output))
+
+		// kubectl prints nothing when all objects were already gone
+		status := DESTROY_STATUS_REMOVED
+
+		if len(bytes.TrimSpace(output)) == 0 {
+			status = DESTROY_STATUS_NOT_PRESENT
+		}
+
+		results = append(results, &DestroyResult{"kustomize", d.Path, b.namespace(d), status})
+	}
+
+	return results, nil
+}
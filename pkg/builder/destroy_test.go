@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"io"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// memoryConfig returns a Helm action configuration backed by in-memory storage holding the given releases
+func memoryConfig(t *testing.T, releases ...*release.Release) *action.Configuration {
+	store := storage.Init(driver.NewMemory())
+
+	for _, rel := range releases {
+		if err := store.Create(rel); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &action.Configuration{
+		Releases:     store,
+		Capabilities: chartutil.DefaultCapabilities,
+		KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+		Log:          func(string, ...interface{}) {},
+	}
+}
+
+// testRelease returns a deployed release whose chart carries the given annotations
+func testRelease(name string, namespace string, annotations map[string]string) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: namespace,
+		Version:   1,
+		Info:      &release.Info{Status: release.StatusDeployed},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: "1.0.0", Annotations: annotations}},
+	}
+}
+
+func TestDestroyRelease(t *testing.T) {
+	owned := map[string]string{PROJECT_ANNOTATION: "web", ENVIRONMENT_ANNOTATION: "production", NAMESPACE_ANNOTATION: ""}
+
+	tests := []struct {
+		name     string
+		release  *release.Release
+		expected string
+		removed  bool
+	}{
+		{name: "owned release", release: testRelease("api", "default", owned), expected: DESTROY_STATUS_REMOVED, removed: true},
+		{name: "missing release", expected: DESTROY_STATUS_NOT_PRESENT},
+		{
+			name:     "release of another project",
+			release:  testRelease("api", "default", map[string]string{PROJECT_ANNOTATION: "other", ENVIRONMENT_ANNOTATION: "production", NAMESPACE_ANNOTATION: ""}),
+			expected: DESTROY_STATUS_NOT_MANAGED,
+		},
+		{name: "release not installed by sym", release: testRelease("api", "default", nil), expected: DESTROY_STATUS_NOT_MANAGED},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var releases []*release.Release
+
+			if test.release != nil {
+				releases = append(releases, test.release)
+			}
+
+			config := memoryConfig(t, releases...)
+
+			b := &HelmBuilder{
+				project:     "web",
+				CommandOpts: &symcommand.CommandOpts{Logger: zerolog.Nop(), Environment: "production"},
+			}
+
+			status, err := b.destroyRelease(config, "api")
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if status != test.expected {
+				t.Errorf("expected status %s, got %s", test.expected, status)
+			}
+
+			_, err = config.Releases.Last("api")
+
+			if removed := err != nil; test.release != nil && removed != test.removed {
+				t.Errorf("expected removed %v, got %v", test.removed, removed)
+			}
+		})
+	}
+}
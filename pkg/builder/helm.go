@@ -2,35 +2,111 @@ package builder
 
 import (
 	"fmt"
-	"os/exec"
+	"os"
 	"path"
-	"strings"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/symbiosis-cloud/cli/pkg/identity"
 	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/cli/pkg/util"
 	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/strvals"
 )
 
+// HelmRepository credentials reference project secrets by name so they are never stored in sym.yaml.
+// For oci:// charts only the credentials are used, the registry is taken from the chart reference.
 type HelmRepository struct {
-	Name string `yaml:"name"`
-	Url  string `yaml:"url"`
+	Name           string `yaml:"name"`
+	Url            string `yaml:"url"`
+	Username       string `yaml:"username,omitempty"`
+	UsernameSecret string `yaml:"usernameSecret,omitempty"`
+	PasswordSecret string `yaml:"passwordSecret,omitempty"`
+	TokenSecret    string `yaml:"tokenSecret,omitempty"`
+	CaFile         string `yaml:"caFile,omitempty"`
+	CaSecret       string `yaml:"caSecret,omitempty"`
 }
 
+// HelmDeployment values are passed to Helm in this order, later sources take precedence:
+// valuesFile, valuesFiles, values, setString and setFile.
+//
+// Wait checks that all Deployments, StatefulSets and DaemonSets of the release are rolled out within
+// Timeout (default 5m). Atomic lets Helm roll back the release itself when the install fails.
+//
+// Namespace overrides the namespace given with --namespace for this release.
+//
+// Chart is either a local chart directory, a chart from Repository or an oci:// reference. Version
+// accepts a semver constraint for remote charts, which sym lock pins to an exact version in sym.lock.
 type HelmDeployment struct {
-	Name       string            `yaml:"name"`
-	Chart      string            `yaml:"chart"`
-	ValuesFile string            `yaml:"valuesFile"`
-	Values     map[string]string `yaml:"values"`
-	Repository *HelmRepository   `yaml:"repository"`
+	Name        string                 `yaml:"name"`
+	Namespace   string                 `yaml:"namespace,omitempty"`
+	Chart       string                 `yaml:"chart"`
+	Version     string                 `yaml:"version,omitempty"`
+	ValuesFile  string                 `yaml:"valuesFile"`
+	ValuesFiles []string               `yaml:"valuesFiles,omitempty"`
+	Values      map[string]interface{} `yaml:"values"`
+	SetString   map[string]string      `yaml:"setString,omitempty"`
+	SetFile     map[string]string      `yaml:"setFile,omitempty"`
+	Repository  *HelmRepository        `yaml:"repository"`
+	DependsOn   []string               `yaml:"dependsOn,omitempty"`
+	Wait        bool                   `yaml:"wait,omitempty"`
+	Timeout     string                 `yaml:"timeout,omitempty"`
+	Atomic      bool                   `yaml:"atomic,omitempty"`
+}
+
+// TimeoutDuration returns the parsed timeout or the Helm default when none is set
+func (d HelmDeployment) TimeoutDuration() (time.Duration, error) {
+	if d.Timeout == "" {
+		return DEFAULT_HELM_TIMEOUT, nil
+	}
+
+	timeout, err := time.ParseDuration(d.Timeout)
+
+	if err != nil {
+		return 0, fmt.Errorf("Invalid timeout %q for Helm deployment %s", d.Timeout, d.Name)
+	}
+
+	return timeout, nil
 }
 
 type HelmBuilder struct {
 	identity    *identity.ClusterIdentity
 	deployments []HelmDeployment
+	waves       [][]HelmDeployment
+	lockfile    *Lockfile
+	charts      map[string]string
+	secrets     SecretResolver
+	home        string
+	registry    *registry.Client
+	caFiles     []string
+	touched     []*touchedRelease
+	mu          sync.Mutex
+	beforeHook  ReleaseHook
+	afterHook   ReleaseHook
 	dir         string
+	project     string
 	CommandOpts *symcommand.CommandOpts
 }
 
+// ReleaseHook is called with the name of a Helm deployment, an error aborts the deployment
+type ReleaseHook func(release string) error
+
+// SetReleaseHooks sets the hooks called right before a release is installed and after it was installed successfully
+func (b *HelmBuilder) SetReleaseHooks(before ReleaseHook, after ReleaseHook) {
+	b.beforeHook = before
+	b.afterHook = after
+}
+
 func (b *HelmBuilder) GetIdentity() *identity.ClusterIdentity {
 	return b.identity
 }
@@ -46,134 +122,605 @@ func (b *HelmBuilder) Build() error {
 		return err
 	}
 
-	for _, deployment := range b.deployments {
-		if deployment.Repository != nil {
-			b.CommandOpts.Logger.Info().Msgf("Adding repository %s", deployment.Repository.Name)
-			args := []string{"repo", "add", deployment.Repository.Name, deployment.Repository.Url}
+	err = b.createHome()
+
+	if err != nil {
+		return err
+	}
+
+	err = b.prepareCharts()
+
+	if err != nil {
+		b.removeHome()
+	}
+
+	return err
+}
+
+// prepareCharts adds the repositories needed by the remote charts. With a lockfile the locked charts are
+// taken from the chart cache or downloaded and verified before anything is deployed.
+func (b *HelmBuilder) prepareCharts() error {
+	var pending []HelmDeployment
+
+	for _, d := range b.deployments {
+		if !b.isRemote(d) {
+			continue
+		}
+
+		if b.lockfile != nil {
+			locked, err := b.lockfile.Verify(d)
 
-			repoAdd := exec.Command("helm", args...)
-			addOutput, err := repoAdd.Output()
 			if err != nil {
 				return err
 			}
 
-			repoUpdate := exec.Command("helm", "repo", "update")
-			updateOutput, err := repoUpdate.CombinedOutput()
-			if err != nil {
-				return err
+			if file, ok := cachedChart(locked.Digest); ok {
+				b.CommandOpts.Logger.Info().Msgf("Using cached chart %s %s for %s", d.Chart, locked.Version, d.Name)
+				b.charts[d.Name] = file
+				continue
 			}
+		}
+
+		pending = append(pending, d)
+	}
+
+	err := b.addRepositories(pending)
+
+	if err != nil {
+		return err
+	}
+
+	if b.lockfile == nil {
+		return nil
+	}
 
-			b.CommandOpts.Logger.Debug().Msg(string(addOutput))
-			b.CommandOpts.Logger.Debug().Msg(string(updateOutput))
+	for _, d := range pending {
+		locked := b.lockfile.Charts[d.Name]
 
+		file, err := b.pullChart(d, locked.Version)
+
+		if err != nil {
+			return err
+		}
+
+		digest, err := chartDigest(file)
+
+		if err != nil {
+			return err
+		}
+
+		if digest != locked.Digest {
+			return fmt.Errorf("Digest of chart %s %s does not match %s (expected %s, got %s)", d.Chart, locked.Version, LOCKFILE_NAME, locked.Digest, digest)
 		}
+
+		b.CommandOpts.Logger.Info().Msgf("Using locked chart %s %s for %s", d.Chart, locked.Version, d.Name)
+
+		cached, err := cacheChart(file, digest)
+
+		os.RemoveAll(filepath.Dir(file))
+
+		if err != nil {
+			return err
+		}
+
+		b.charts[d.Name] = cached
 	}
 
 	return nil
 }
 
-func (b *HelmBuilder) Deploy() error {
-	b.CommandOpts.Logger.Info().Msg("Using Helm for deployment")
-	w := new(errgroup.Group)
+// Lock resolves every remote chart to the exact version and digest matching its version constraint
+func (b *HelmBuilder) Lock() (*Lockfile, error) {
+	err := meetsRequirements(b)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.createHome()
+
+	if err != nil {
+		return nil, err
+	}
+	defer b.removeHome()
+
+	var remote []HelmDeployment
 
 	for _, d := range b.deployments {
+		if b.isRemote(d) {
+			remote = append(remote, d)
+		}
+	}
+
+	err = b.addRepositories(remote)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lockfile := &Lockfile{Charts: map[string]*LockedChart{}}
+
+	for _, d := range remote {
+		file, err := b.pullChart(d, d.Version)
+
+		if err != nil {
+			return nil, err
+		}
+
+		version, err := chartVersion(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		digest, err := chartDigest(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the next apply can use the chart without downloading it again
+		if _, err := cacheChart(file, digest); err != nil {
+			b.CommandOpts.Logger.Warn().Msgf("Could not cache chart %s: %v", d.Chart, err)
+		}
+
+		os.RemoveAll(filepath.Dir(file))
+
+		b.CommandOpts.Logger.Info().Msgf("Locked %s to %s %s", d.Name, d.Chart, version)
+
+		lockfile.Charts[d.Name] = &LockedChart{
+			Chart:      d.Chart,
+			Repository: repositoryUrl(d),
+			Constraint: d.Version,
+			Version:    version,
+			Digest:     digest,
+		}
+	}
+
+	return lockfile, nil
+}
+
+// addRepositories adds each classic repository and logs in to each OCI registry used by the deployments once.
+// Adding a repository downloads its index, the isolated Helm home never holds other repositories to update.
+func (b *HelmBuilder) addRepositories(deployments []HelmDeployment) error {
+	settings := b.settings()
+	repositoryFile := repo.NewFile()
+	registries := map[string]bool{}
+
+	var caFiles []string
+
+	for _, deployment := range deployments {
+		credentials, err := b.credentials(deployment.Repository)
+
+		if err != nil {
+			return err
+		}
 
-		// avoid issues with variable capturing
-		deployment := d
+		if IsOCIChart(deployment.Chart) {
+			host := registryHost(deployment.Chart)
 
-		w.Go(func() error {
-			err := b.Install(deployment)
+			if credentials == nil || registries[host] {
+				continue
+			}
+
+			registries[host] = true
+
+			if credentials.caFile != "" {
+				caFiles = append(caFiles, credentials.caFile)
+			}
+
+			if credentials.username == "" && credentials.password == "" {
+				continue
+			}
+
+			b.CommandOpts.Logger.Info().Msgf("Logging in to registry %s", host)
+
+			registryClient, err := b.registryClient()
 
 			if err != nil {
 				return err
 			}
 
-			return nil
-		})
+			err = registryClient.Login(
+				host,
+				registry.LoginOptBasicAuth(credentials.username, credentials.password),
+				registry.LoginOptTLSClientConfig("", "", credentials.caFile),
+			)
+
+			if err != nil {
+				return &HelmError{Action: "registry login", Release: deployment.Name, Err: err}
+			}
+
+			continue
+		}
+
+		repository := deployment.Repository
+
+		if existing := repositoryFile.Get(repository.Name); existing != nil {
+			if existing.URL != repository.Url {
+				return fmt.Errorf("Repository %s is configured with different urls: %s and %s", repository.Name, existing.URL, repository.Url)
+			}
+
+			continue
+		}
+
+		b.CommandOpts.Logger.Info().Msgf("Adding repository %s", repository.Name)
+
+		entry := &repo.Entry{
+			Name: repository.Name,
+			URL:  repository.Url,
+		}
+
+		if credentials != nil {
+			entry.Username = credentials.username
+			entry.Password = credentials.password
+			entry.CAFile = credentials.caFile
+		}
+
+		chartRepository, err := repo.NewChartRepository(entry, getter.All(settings))
+
+		if err != nil {
+			return err
+		}
+
+		chartRepository.CachePath = settings.RepositoryCache
+
+		_, err = chartRepository.DownloadIndexFile()
+
+		if err != nil {
+			return &HelmError{Action: "repository add", Release: deployment.Name, Err: err}
+		}
+
+		repositoryFile.Update(entry)
+	}
+
+	// the registry client was created with the Helm home, create it again so pulls trust the CA bundles
+	if len(caFiles) > 0 {
+		b.caFiles = caFiles
+
+		client, err := b.newRegistryClient()
+
+		if err != nil {
+			return err
+		}
+
+		b.registry = client
 	}
 
-	if err := w.Wait(); err != nil {
+	if len(repositoryFile.Repositories) == 0 {
+		return nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(settings.RepositoryConfig), 0700)
+
+	if err != nil {
 		return err
 	}
 
+	return repositoryFile.WriteFile(settings.RepositoryConfig, 0600)
+}
+
+// pullChart downloads a remote chart into the Helm home and returns the path of the package. The caller removes
+// the directory of the package once it has been cached, anything left is removed with the Helm home.
+func (b *HelmBuilder) pullChart(d HelmDeployment, version string) (string, error) {
+	if b.home == "" {
+		return "", fmt.Errorf("Cannot pull chart %s without a Helm home", d.Chart)
+	}
+
+	dir, err := os.MkdirTemp(b.home, "chart-")
+
+	if err != nil {
+		return "", err
+	}
+
+	config, err := b.actionConfig(b.CommandOpts.Namespace)
+
+	if err != nil {
+		return "", err
+	}
+
+	pull := action.NewPullWithOpts(action.WithConfig(config))
+	pull.Settings = b.settings()
+	pull.Version = version
+	pull.DestDir = dir
+
+	_, err = pull.Run(d.Chart)
+
+	if err != nil {
+		return "", &HelmError{Action: "pull", Release: d.Name, Err: err}
+	}
+
+	packages, err := filepath.Glob(filepath.Join(dir, "*.tgz"))
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(packages) != 1 {
+		return "", fmt.Errorf("Expected a single chart package for %s, found %d", d.Chart, len(packages))
+	}
+
+	return packages[0], nil
+}
+
+func (b *HelmBuilder) isRemote(d HelmDeployment) bool {
+	if IsOCIChart(d.Chart) {
+		return true
+	}
+
+	return d.Repository != nil && !FileExists(path.Join(b.expandPaths(d.Chart), "Chart.yaml"))
+}
+
+// chartVersion reads the version from the Chart.yaml of a packaged chart
+func chartVersion(file string) (string, error) {
+	chart, err := loader.Load(file)
+
+	if err != nil {
+		return "", fmt.Errorf("Could not read chart %s: %v", file, err)
+	}
+
+	if chart.Metadata.Version == "" {
+		return "", fmt.Errorf("Chart %s has no version", file)
+	}
+
+	return chart.Metadata.Version, nil
+}
+
+func (b *HelmBuilder) Deploy() error {
+	b.CommandOpts.Logger.Info().Msg("Using Helm for deployment")
+
+	b.touched = nil
+
+	// releases within a wave are independent, dependents wait for the previous wave to succeed
+	for i, wave := range b.waves {
+		b.CommandOpts.Logger.Debug().Msgf("Installing wave %d of %d (%d releases)", i+1, len(b.waves), len(wave))
+
+		w := new(errgroup.Group)
+
+		for _, d := range wave {
+
+			// avoid issues with variable capturing
+			deployment := d
+
+			w.Go(func() error {
+				err := b.Install(deployment)
+
+				if err != nil {
+					return err
+				}
+
+				return nil
+			})
+		}
+
+		if err := w.Wait(); err != nil {
+			b.Rollback()
+
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (b *HelmBuilder) Install(d HelmDeployment) error {
 	b.CommandOpts.Logger.Info().Msgf("Installing Helm chart %s", d.Name)
-	kubeConfig := b.GetIdentity().KubeConfigPath
 
-	namespace := b.CommandOpts.Namespace
+	namespace := b.namespace(d)
 
-	// check if the helm chart is already installed
-	releaseExists := true
+	timeout, err := d.TimeoutDuration()
 
-	existArgs := []string{"status", "--kubeconfig", kubeConfig, d.Name, "--namespace", namespace}
-	existsCmd := exec.Command("helm", existArgs...)
-	existsOutput, err := existsCmd.CombinedOutput()
+	if err != nil {
+		return err
+	}
 
-	b.CommandOpts.Logger.Debug().Msgf("Executing: helm %s", strings.Join(existArgs, " "))
+	config, err := b.actionConfig(namespace)
 
 	if err != nil {
-		if !strings.Contains(string(existsOutput), "not found") {
-			return err
-		}
+		return err
+	}
+
+	// check if the helm chart is already installed
+	revision, err := releaseRevision(config, d.Name)
 
-		releaseExists = false
+	if err != nil {
+		return err
+	}
+
+	helmChart, err := b.loadChart(config, d)
+
+	if err != nil {
+		return err
 	}
 
-	b.CommandOpts.Logger.Debug().Err(err)
-	b.CommandOpts.Logger.Debug().Msg(string(existsOutput))
+	values, err := b.values(d)
+
+	if err != nil {
+		return err
+	}
 
-	chart := d.Chart
+	if b.beforeHook != nil {
+		err = b.beforeHook(d.Name)
 
-	if d.Repository == nil {
-		chart = b.expandPaths(d.Chart)
+		if err != nil {
+			return err
+		}
 	}
 
-	var args []string
+	b.touch(d.Name, namespace, revision, timeout)
 
-	if releaseExists {
+	if revision > 0 {
 		b.CommandOpts.Logger.Info().Msgf("Release %s already exists, upgrading...", d.Name)
-		args = []string{"upgrade", "--kubeconfig", kubeConfig, "--namespace", namespace}
+
+		upgrade := action.NewUpgrade(config)
+		upgrade.Namespace = namespace
+		upgrade.Timeout = timeout
+		upgrade.Atomic = d.Atomic
+		upgrade.PostRenderer = &projectLabeler{b.project}
+
+		_, err = upgrade.Run(d.Name, helmChart, values)
+
+		if err != nil {
+			return &HelmError{Action: "upgrade", Release: d.Name, Err: err}
+		}
 	} else {
-		args = []string{"install", "--kubeconfig", kubeConfig, "--namespace", namespace, d.Name, chart}
+		install := action.NewInstall(config)
+		install.ReleaseName = d.Name
+		install.Namespace = namespace
+		install.Timeout = timeout
+		install.Atomic = d.Atomic
+		install.PostRenderer = &projectLabeler{b.project}
+
+		_, err = install.Run(helmChart, values)
+
+		if err != nil {
+			return &HelmError{Action: "install", Release: d.Name, Err: err}
+		}
 	}
 
-	for key, value := range d.Values {
-		args = append(args, "--set", fmt.Sprintf("%s=%s", key, value))
+	if d.Wait {
+		b.CommandOpts.Logger.Info().Msgf("Waiting up to %s for release %s to become ready", timeout, d.Name)
+
+		clientset, err := util.GetKubernetesClient(b.GetIdentity().KubeConfigPath)
+
+		if err != nil {
+			return err
+		}
+
+		err = waitForRelease(clientset, namespace, d.Name, timeout)
+
+		if err != nil {
+			return err
+		}
 	}
 
-	if d.ValuesFile != "" {
-		args = append(args, "-f", b.expandPaths(d.ValuesFile))
+	if b.afterHook != nil {
+		return b.afterHook(d.Name)
 	}
 
-	if releaseExists {
-		args = append(args, d.Name, chart)
+	return nil
+}
+
+// loadChart loads the chart of a deployment, preferring a chart downloaded for sym.lock, and marks it with the project
+func (b *HelmBuilder) loadChart(config *action.Configuration, d HelmDeployment) (*chart.Chart, error) {
+	file, ok := b.charts[d.Name]
+
+	if !ok && !b.isRemote(d) {
+		file = b.expandPaths(d.Chart)
+	} else if !ok {
+		locate := action.NewInstall(config).ChartPathOptions
+		locate.Version = d.Version
+
+		located, err := locate.LocateChart(d.Chart, b.settings())
+
+		if err != nil {
+			return nil, &HelmError{Action: "locate chart", Release: d.Name, Err: err}
+		}
+
+		file = located
 	}
 
-	b.CommandOpts.Logger.Debug().Msgf("Running helm %s", strings.Join(args, " "))
+	loaded, err := loader.Load(file)
 
-	repoAdd := exec.Command("helm", args...)
-	output, err := repoAdd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("Helm failed. Full output: %s", output)
+		return nil, fmt.Errorf("Could not load chart %s: %v", d.Chart, err)
 	}
 
-	b.CommandOpts.Logger.Debug().Msg(string(output))
+	labelChart(loaded, b.project, b.CommandOpts.Environment, b.CommandOpts.Namespace)
 
-	return nil
+	return loaded, nil
 }
 
-func (b *HelmBuilder) requirements() ([]Requirement, error) {
-	requirements := []Requirement{
-		&CommandRequirement{"helm"},
+// values merges all values of a deployment in the same order as the Helm CLI applies its flags
+func (b *HelmBuilder) values(d HelmDeployment) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+
+	for _, valuesFile := range append([]string{d.ValuesFile}, d.ValuesFiles...) {
+		if valuesFile == "" {
+			continue
+		}
+
+		content, err := os.ReadFile(b.expandPaths(valuesFile))
+
+		if err != nil {
+			return nil, err
+		}
+
+		var fileValues map[string]interface{}
+
+		err = yaml.Unmarshal(content, &fileValues)
+
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse values file %s: %v", valuesFile, err)
+		}
+
+		values = MergeValues(values, NormalizeValues(fileValues).(map[string]interface{}))
+	}
+
+	inline, err := ExpandValues(NormalizeValues(d.Values).(map[string]interface{}))
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid values for %s: %v", d.Name, err)
+	}
+
+	values = MergeValues(values, inline)
+
+	for _, key := range sortedKeys(d.SetString) {
+		err := strvals.ParseIntoString(fmt.Sprintf("%s=%s", key, d.SetString[key]), values)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid setString %s for %s: %v", key, d.Name, err)
+		}
+	}
+
+	for _, key := range sortedKeys(d.SetFile) {
+		err := strvals.ParseIntoFile(fmt.Sprintf("%s=%s", key, b.expandPaths(d.SetFile[key])), values, func(file []rune) (interface{}, error) {
+			content, err := os.ReadFile(string(file))
+
+			return string(content), err
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid setFile %s for %s: %v", key, d.Name, err)
+		}
 	}
 
+	return values, nil
+}
+
+func (b *HelmBuilder) requirements() ([]Requirement, error) {
+	var requirements []Requirement
+
 	for _, d := range b.deployments {
-		if d.ValuesFile != "" {
-			b.CommandOpts.Logger.Info().Msgf("Using values file %s", d.ValuesFile)
-			requirements = append(requirements, &FileRequirement{b.expandPaths(d.ValuesFile)})
+		for _, valuesFile := range append([]string{d.ValuesFile}, d.ValuesFiles...) {
+			if valuesFile != "" {
+				b.CommandOpts.Logger.Info().Msgf("Using values file %s", valuesFile)
+				requirements = append(requirements, &FileRequirement{b.expandPaths(valuesFile)})
+			}
+		}
+
+		for _, setFile := range d.SetFile {
+			requirements = append(requirements, &FileRequirement{b.expandPaths(setFile)})
+		}
+
+		if _, err := d.TimeoutDuration(); err != nil {
+			return nil, err
+		}
+
+		if d.Repository != nil {
+			err := d.Repository.ValidateCredentials()
+
+			if err != nil {
+				return nil, fmt.Errorf("Invalid repository credentials for Helm chart %s: %v", d.Name, err)
+			}
+
+			if d.Repository.CaFile != "" {
+				requirements = append(requirements, &FileRequirement{b.expandPaths(d.Repository.CaFile)})
+			}
+		}
+
+		if IsOCIChart(d.Chart) {
+			continue
 		}
 
 		chartFile := path.Join(b.expandPaths(d.Chart), "Chart.yaml")
@@ -197,17 +744,52 @@ func (b *HelmBuilder) requirements() ([]Requirement, error) {
 }
 
 func (b *HelmBuilder) expandPaths(path string) string {
-	if strings.Contains(path, "./") {
-		return strings.Replace(path, "./", b.dir+"/", 1)
+	return ExpandPath(b.dir, path)
+}
+
+func NewHelmBuilder(deployments []HelmDeployment, dir string, project string, opts *symcommand.CommandOpts, secrets SecretResolver) (*HelmBuilder, error) {
+	waves, err := resolveHelmWaves(deployments)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return path
-}
+	for _, d := range deployments {
+		if d.Version == "" {
+			continue
+		}
+
+		if _, err := semver.NewConstraint(d.Version); err != nil {
+			return nil, fmt.Errorf("Invalid version constraint %s for Helm deployment %s: %v", d.Version, d.Name, err)
+		}
+	}
+
+	lockfile, err := LoadLockfile(LockfilePath(dir))
+
+	if err != nil {
+		return nil, err
+	}
 
-func NewHelmBuilder(deployments []HelmDeployment, dir string, opts *symcommand.CommandOpts) *HelmBuilder {
 	return &HelmBuilder{
 		deployments: deployments,
+		waves:       waves,
+		lockfile:    lockfile,
+		charts:      map[string]string{},
+		secrets:     secrets,
 		dir:         dir,
+		project:     project,
 		CommandOpts: opts,
+	}, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
 	}
+
+	sort.Strings(keys)
+
+	return keys
 }
@@ -0,0 +1,238 @@
+package builder
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// settings returns the Helm environment of the builder. After createHome all repositories, registry logins
+// and caches live in an isolated Helm home, so concurrent runs never share them with each other or with the
+// user's Helm config.
+func (b *HelmBuilder) settings() *cli.EnvSettings {
+	settings := cli.New()
+
+	if b.home != "" {
+		settings.RepositoryConfig = path.Join(b.home, "config", "repositories.yaml")
+		settings.RepositoryCache = path.Join(b.home, "cache", "repository")
+		settings.RegistryConfig = path.Join(b.home, "config", "registry", "config.json")
+	}
+
+	return settings
+}
+
+// actionConfig returns a Helm action configuration for the cluster of the builder identity
+func (b *HelmBuilder) actionConfig(namespace string) (*action.Configuration, error) {
+	registryClient, err := b.registryClient()
+
+	if err != nil {
+		return nil, err
+	}
+
+	config := &action.Configuration{RegistryClient: registryClient}
+
+	if b.GetIdentity() == nil {
+		return config, nil
+	}
+
+	kubeConfig := b.GetIdentity().KubeConfigPath
+
+	flags := genericclioptions.NewConfigFlags(false)
+	flags.KubeConfig = &kubeConfig
+	flags.Namespace = &namespace
+
+	err = config.Init(flags, namespace, os.Getenv("HELM_DRIVER"), func(format string, v ...interface{}) {
+		b.CommandOpts.Logger.Debug().Msgf(format, v...)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (b *HelmBuilder) registryClient() (*registry.Client, error) {
+	if b.registry != nil {
+		return b.registry, nil
+	}
+
+	return b.newRegistryClient()
+}
+
+func (b *HelmBuilder) newRegistryClient() (*registry.Client, error) {
+	options := []registry.ClientOption{
+		registry.ClientOptCredentialsFile(b.settings().RegistryConfig),
+		registry.ClientOptWriter(io.Discard),
+	}
+
+	if len(b.caFiles) > 0 {
+		client, err := registryHTTPClient(b.caFiles)
+
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, registry.ClientOptHTTPClient(client))
+	}
+
+	return registry.NewClient(options...)
+}
+
+// registryHTTPClient trusts the CA bundles of the registries in addition to the system roots. All registries
+// of a run share the client, like they share the Helm home.
+func registryHTTPClient(caFiles []string) (*http.Client, error) {
+	pool, err := x509.SystemCertPool()
+
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	for _, file := range caFiles {
+		ca, err := os.ReadFile(file)
+
+		if err != nil {
+			return nil, fmt.Errorf("Could not read CA bundle: %v", err)
+		}
+
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("No certificates found in CA bundle %s", file)
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+func (b *HelmBuilder) createHome() error {
+	if b.home != "" {
+		return nil
+	}
+
+	home, err := os.MkdirTemp(os.TempDir(), "sym-helm-")
+
+	if err != nil {
+		return err
+	}
+
+	b.CommandOpts.Logger.Debug().Msgf("Using Helm home %s", home)
+
+	b.home = home
+
+	b.registry, err = b.registryClient()
+
+	if err != nil {
+		b.removeHome()
+		return err
+	}
+
+	return nil
+}
+
+// Close removes the Helm home created by Build together with the repositories, registry logins and CA
+// bundles in it. Releases can still be rolled back or inspected afterwards.
+func (b *HelmBuilder) Close() {
+	b.removeHome()
+}
+
+func (b *HelmBuilder) removeHome() {
+	if b.home == "" {
+		return
+	}
+
+	os.RemoveAll(b.home)
+	b.home = ""
+	b.registry = nil
+	b.caFiles = nil
+}
+
+// ChartCacheDir holds the charts downloaded for sym.lock, named by their digest
+func ChartCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(home, ".symbiosis", "charts"), nil
+}
+
+// cachedChart returns the cached package for a digest if it exists and is intact
+func cachedChart(digest string) (string, bool) {
+	dir, err := ChartCacheDir()
+
+	if err != nil {
+		return "", false
+	}
+
+	file := path.Join(dir, strings.TrimPrefix(digest, "sha256:")+".tgz")
+
+	if actual, err := chartDigest(file); err != nil || actual != digest {
+		return "", false
+	}
+
+	return file, true
+}
+
+// cacheChart copies a downloaded package into the chart cache. The copy is renamed into place so
+// concurrent runs never read a partially written chart.
+func cacheChart(file string, digest string) (string, error) {
+	dir, err := ChartCacheDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(dir, 0700)
+
+	if err != nil {
+		return "", err
+	}
+
+	src, err := os.Open(file)
+
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(dir, "download-*")
+
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, src)
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	cached := filepath.Join(dir, strings.TrimPrefix(digest, "sha256:")+".tgz")
+
+	err = os.Rename(tmp.Name(), cached)
+
+	if err != nil {
+		return "", err
+	}
+
+	return cached, nil
+}
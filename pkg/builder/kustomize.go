@@ -0,0 +1,194 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/symbiosis-cloud/cli/pkg/identity"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+)
+
+var kustomizationFiles = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// KustomizeDeployment is applied to Namespace or the namespace given with --namespace when it is empty
+type KustomizeDeployment struct {
+	Path      string `yaml:"path"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type KustomizeBuilder struct {
+	identity    *identity.ClusterIdentity
+	deployments []KustomizeDeployment
+	manifests   map[string][]byte
+	dir         string
+	project     string
+	CommandOpts *symcommand.CommandOpts
+}
+
+func (b *KustomizeBuilder) GetIdentity() *identity.ClusterIdentity {
+	return b.identity
+}
+
+func (b *KustomizeBuilder) SetIdentity(identity *identity.ClusterIdentity) {
+	b.identity = identity
+}
+
+func (b *KustomizeBuilder) Build() error {
+	err := meetsRequirements(b)
+
+	if err != nil {
+		return err
+	}
+
+	for _, deployment := range b.deployments {
+		output, err := b.build(deployment)
+
+		if err != nil {
+			return err
+		}
+
+		b.manifests[deployment.Path] = output
+	}
+
+	return nil
+}
+
+func (b *KustomizeBuilder) build(deployment KustomizeDeployment) ([]byte, error) {
+	overlay := b.expandPaths(deployment.Path)
+
+	b.CommandOpts.Logger.Info().Msgf("Building kustomization %s", deployment.Path)
+	b.CommandOpts.Logger.Debug().Msgf("Running kubectl kustomize %s", overlay)
+
+	build := exec.Command("kubectl", "kustomize", overlay)
+	output, err := build.Output()
+
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("Kustomize build of %s failed. Full output: %s", deployment.Path, exitErr.Stderr)
+		}
+		return nil, err
+	}
+
+	return (&projectLabeler{b.project}).label(output)
+}
+
+// manifest returns the built kustomization, building it when Build did not run
+func (b *KustomizeBuilder) manifest(deployment KustomizeDeployment) ([]byte, error) {
+	if manifest, ok := b.manifests[deployment.Path]; ok {
+		return manifest, nil
+	}
+
+	return b.build(deployment)
+}
+
+func (b *KustomizeBuilder) Deploy() error {
+	b.CommandOpts.Logger.Info().Msg("Using Kustomize for deployment")
+
+	for _, deployment := range b.deployments {
+		err := b.Apply(deployment)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *KustomizeBuilder) Apply(d KustomizeDeployment) error {
+	manifest, ok := b.manifests[d.Path]
+
+	if !ok {
+		return fmt.Errorf("Kustomization %s has not been built yet", d.Path)
+	}
+
+	b.CommandOpts.Logger.Info().Msgf("Applying kustomization %s", d.Path)
+
+	args := []string{"apply", "--kubeconfig", b.GetIdentity().KubeConfigPath, "--namespace", b.namespace(d), "-f", "-"}
+
+	b.CommandOpts.Logger.Debug().Msgf("Running kubectl %s", strings.Join(args, " "))
+
+	apply := exec.Command("kubectl", args...)
+	apply.Stdin = bytes.NewReader(manifest)
+
+	output, err := apply.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Kubectl apply failed. Full output: %s", output)
+	}
+
+	b.CommandOpts.Logger.Debug().Msg(string(output))
+
+	return nil
+}
+
+// Namespaces returns the namespaces the kustomizations are applied to
+func (b *KustomizeBuilder) Namespaces() []string {
+	var namespaces []string
+
+	for _, d := range b.deployments {
+		namespaces = append(namespaces, b.namespace(d))
+	}
+
+	return namespaces
+}
+
+func (b *KustomizeBuilder) namespace(d KustomizeDeployment) string {
+	if d.Namespace != "" {
+		return d.Namespace
+	}
+
+	return b.CommandOpts.Namespace
+}
+
+func (b *KustomizeBuilder) requirements() ([]Requirement, error) {
+	requirements := []Requirement{
+		// kustomize is built into kubectl since 1.14
+		&VersionRequirement{Command: "kubectl", Args: []string{"version", "--client"}, Constraint: ">= 1.14"},
+	}
+
+	for _, d := range b.deployments {
+		if d.Path == "" {
+			return nil, fmt.Errorf("Invalid kustomize configuration, path is required")
+		}
+
+		kustomization := KustomizationFile(b.expandPaths(d.Path))
+
+		if kustomization == "" {
+			return nil, fmt.Errorf("Kustomization not found in path %s", b.expandPaths(d.Path))
+		}
+
+		requirements = append(requirements, &FileRequirement{kustomization})
+	}
+
+	return requirements, nil
+}
+
+// KustomizationFile returns the kustomization file in dir or an empty string if there is none
+func KustomizationFile(dir string) string {
+	for _, file := range kustomizationFiles {
+		kustomization := path.Join(dir, file)
+
+		if FileExists(kustomization) {
+			return kustomization
+		}
+	}
+
+	return ""
+}
+
+func (b *KustomizeBuilder) expandPaths(path string) string {
+	return ExpandPath(b.dir, path)
+}
+
+func NewKustomizeBuilder(deployments []KustomizeDeployment, dir string, project string, opts *symcommand.CommandOpts) *KustomizeBuilder {
+	return &KustomizeBuilder{
+		deployments: deployments,
+		manifests:   make(map[string][]byte, len(deployments)),
+		dir:         dir,
+		project:     project,
+		CommandOpts: opts,
+	}
+}
@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/symbiosis-cloud/cli/pkg/manifest"
+	"helm.sh/helm/v3/pkg/chart"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PROJECT_LABEL holds the project name as a label value, PROJECT_ANNOTATION the unmodified name
+	PROJECT_LABEL      = "symbiosis.host/project"
+	PROJECT_ANNOTATION = "symbiosis.host/project"
+
+	// ENVIRONMENT_LABEL holds the sym.yaml environment of the deploy that created a namespace
+	ENVIRONMENT_LABEL = "symbiosis.host/environment"
+
+	// ENVIRONMENT_ANNOTATION and NAMESPACE_ANNOTATION identify the deploy of a chart together with the project,
+	// so deploys of the same project with another environment or --namespace, such as previews, are told apart
+	ENVIRONMENT_ANNOTATION = "symbiosis.host/environment"
+	NAMESPACE_ANNOTATION   = "symbiosis.host/namespace"
+)
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// LabelValue turns any string into a valid label value
+func LabelValue(value string) string {
+	value = strings.Trim(invalidLabelChars.ReplaceAllString(value, "-"), "-_.")
+
+	if len(value) > 63 {
+		value = strings.Trim(value[:63], "-_.")
+	}
+
+	return value
+}
+
+// projectLabeler adds the project label and annotation to every object rendered by Helm or kustomize
+type projectLabeler struct {
+	project string
+}
+
+func (l *projectLabeler) Run(rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	content, err := l.label(rendered.Bytes())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(content), nil
+}
+
+func (l *projectLabeler) label(content []byte) ([]byte, error) {
+	objects, err := manifest.Parse(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+
+	for _, object := range objects {
+		labels := object.GetLabels()
+
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		labels[PROJECT_LABEL] = LabelValue(l.project)
+		object.SetLabels(labels)
+
+		annotations := object.GetAnnotations()
+
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[PROJECT_ANNOTATION] = l.project
+		object.SetAnnotations(annotations)
+
+		data, err := yaml.Marshal(object.Object)
+
+		if err != nil {
+			return nil, err
+		}
+
+		out.WriteString("---\n")
+		out.Write(data)
+	}
+
+	return out.Bytes(), nil
+}
+
+// labelChart marks a chart with the project, environment and namespace of the deploy. Helm stores the chart
+// with every release so installed releases can be traced back to the deploy that installed them.
+func labelChart(helmChart *chart.Chart, project string, environment string, namespace string) {
+	if helmChart.Metadata.Annotations == nil {
+		helmChart.Metadata.Annotations = map[string]string{}
+	}
+
+	helmChart.Metadata.Annotations[PROJECT_ANNOTATION] = project
+	helmChart.Metadata.Annotations[ENVIRONMENT_ANNOTATION] = environment
+	helmChart.Metadata.Annotations[NAMESPACE_ANNOTATION] = namespace
+}
+
+// deployedBy reports whether a chart was installed by a deploy of the project with the same environment and namespace
+func deployedBy(helmChart *chart.Chart, project string, environment string, namespace string) bool {
+	if helmChart == nil || helmChart.Metadata == nil {
+		return false
+	}
+
+	annotations := helmChart.Metadata.Annotations
+
+	if annotations[PROJECT_ANNOTATION] != project || annotations[ENVIRONMENT_ANNOTATION] != environment {
+		return false
+	}
+
+	namespaceAnnotation, ok := annotations[NAMESPACE_ANNOTATION]
+
+	return ok && namespaceAnnotation == namespace
+}
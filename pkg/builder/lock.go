@@ -0,0 +1,120 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	LOCKFILE_NAME = "sym.lock"
+	OCI_PREFIX    = "oci://"
+)
+
+const lockfileHeader = "# This file is generated by sym lock, do not edit it by hand.\n"
+
+// LockedChart pins a remote chart to the exact version and digest resolved by sym lock
+type LockedChart struct {
+	Chart      string `yaml:"chart"`
+	Repository string `yaml:"repository,omitempty"`
+	Constraint string `yaml:"constraint,omitempty"`
+	Version    string `yaml:"version"`
+	Digest     string `yaml:"digest"`
+}
+
+type Lockfile struct {
+	Charts map[string]*LockedChart `yaml:"charts"`
+}
+
+// Verify checks that the lockfile still matches the deployment it was generated for
+func (l *Lockfile) Verify(d HelmDeployment) (*LockedChart, error) {
+	locked := l.Charts[d.Name]
+
+	if locked == nil {
+		return nil, fmt.Errorf("Helm deployment %s is missing from %s, please run sym lock", d.Name, LOCKFILE_NAME)
+	}
+
+	if locked.Chart != d.Chart || locked.Repository != repositoryUrl(d) || locked.Constraint != d.Version {
+		return nil, fmt.Errorf("%s is out of date for Helm deployment %s, please run sym lock", LOCKFILE_NAME, d.Name)
+	}
+
+	return locked, nil
+}
+
+func (l *Lockfile) Write(file string) error {
+	content, err := yaml.Marshal(l)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, append([]byte(lockfileHeader), content...), 0644)
+}
+
+// LoadLockfile reads a lockfile, a missing file results in a nil lockfile
+func LoadLockfile(file string) (*Lockfile, error) {
+	content, err := os.ReadFile(file)
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var lockfile *Lockfile
+
+	err = yaml.Unmarshal(content, &lockfile)
+
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse %s: %v", file, err)
+	}
+
+	if lockfile == nil {
+		lockfile = &Lockfile{}
+	}
+
+	if lockfile.Charts == nil {
+		lockfile.Charts = map[string]*LockedChart{}
+	}
+
+	return lockfile, nil
+}
+
+func LockfilePath(dir string) string {
+	return filepath.Join(dir, LOCKFILE_NAME)
+}
+
+func IsOCIChart(chart string) bool {
+	return strings.HasPrefix(chart, OCI_PREFIX)
+}
+
+func repositoryUrl(d HelmDeployment) string {
+	if d.Repository == nil {
+		return ""
+	}
+
+	return d.Repository.Url
+}
+
+// chartDigest returns the sha256 digest of a packaged chart, matching the digest in a repository index
+func chartDigest(file string) (string, error) {
+	f, err := os.Open(file)
+
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("sha256:%x", hash.Sum(nil)), nil
+}
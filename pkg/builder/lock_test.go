@@ -0,0 +1,158 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLockfileVerify(t *testing.T) {
+	lockfile := &Lockfile{Charts: map[string]*LockedChart{
+		"metrics": {
+			Chart:      "prometheus-community/kube-state-metrics",
+			Repository: "https://prometheus-community.github.io/helm-charts",
+			Constraint: "^4.0.0",
+			Version:    "4.24.0",
+			Digest:     "sha256:abc",
+		},
+		"podinfo": {
+			Chart:   "oci://ghcr.io/stefanprodan/charts/podinfo",
+			Version: "6.2.3",
+			Digest:  "sha256:def",
+		},
+	}}
+
+	metrics := HelmDeployment{
+		Name:       "metrics",
+		Chart:      "prometheus-community/kube-state-metrics",
+		Version:    "^4.0.0",
+		Repository: &HelmRepository{Name: "prometheus-community", Url: "https://prometheus-community.github.io/helm-charts"},
+	}
+
+	tests := []struct {
+		name       string
+		deployment func() HelmDeployment
+		version    string
+		err        string
+	}{
+		{
+			name:       "repository chart",
+			deployment: func() HelmDeployment { return metrics },
+			version:    "4.24.0",
+		},
+		{
+			name: "oci chart without constraint",
+			deployment: func() HelmDeployment {
+				return HelmDeployment{Name: "podinfo", Chart: "oci://ghcr.io/stefanprodan/charts/podinfo"}
+			},
+			version: "6.2.3",
+		},
+		{
+			name: "credentials do not affect the lock",
+			deployment: func() HelmDeployment {
+				d := metrics
+				d.Repository = &HelmRepository{Name: "prometheus-community", Url: metrics.Repository.Url, Username: "deploy"}
+				return d
+			},
+			version: "4.24.0",
+		},
+		{
+			name: "missing deployment",
+			deployment: func() HelmDeployment {
+				return HelmDeployment{Name: "other", Chart: "oci://ghcr.io/example/other"}
+			},
+			err: "Helm deployment other is missing from sym.lock",
+		},
+		{
+			name: "changed constraint",
+			deployment: func() HelmDeployment {
+				d := metrics
+				d.Version = "^5.0.0"
+				return d
+			},
+			err: "sym.lock is out of date for Helm deployment metrics",
+		},
+		{
+			name: "changed chart",
+			deployment: func() HelmDeployment {
+				d := metrics
+				d.Chart = "prometheus-community/prometheus"
+				return d
+			},
+			err: "out of date",
+		},
+		{
+			name: "changed repository",
+			deployment: func() HelmDeployment {
+				d := metrics
+				d.Repository = &HelmRepository{Name: "mirror", Url: "https://charts.example.com"}
+				return d
+			},
+			err: "out of date",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			locked, err := lockfile.Verify(test.deployment())
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if locked.Version != test.version {
+				t.Errorf("expected version %s, got %s", test.version, locked.Version)
+			}
+		})
+	}
+}
+
+func TestLockfileRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), LOCKFILE_NAME)
+
+	missing, err := LoadLockfile(file)
+
+	if err != nil || missing != nil {
+		t.Fatalf("expected no lockfile and no error, got %v and %v", missing, err)
+	}
+
+	lockfile := &Lockfile{Charts: map[string]*LockedChart{
+		"podinfo": {Chart: "oci://ghcr.io/stefanprodan/charts/podinfo", Constraint: "6.2.x", Version: "6.2.3", Digest: "sha256:def"},
+	}}
+
+	err = lockfile.Write(file)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(file)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(content), lockfileHeader) {
+		t.Errorf("expected the generated header, got %q", content)
+	}
+
+	loaded, err := LoadLockfile(file)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded, lockfile) {
+		t.Errorf("expected %#v, got %#v", lockfile, loaded)
+	}
+}
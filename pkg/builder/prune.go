@@ -0,0 +1,146 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// ManagedRelease is a Helm release installed by sym for the project of the builder
+type ManagedRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Chart     string `json:"chart"`
+	Revision  int    `json:"revision"`
+	Status    string `json:"status"`
+}
+
+// UndeclaredReleases returns the releases in any namespace that were installed by a deploy of the project with
+// the same environment and namespace and are no longer in sym.yaml. Releases of other environments and previews
+// of the project are never returned.
+func (b *HelmBuilder) UndeclaredReleases() ([]*ManagedRelease, error) {
+	if b.project == "" {
+		return nil, fmt.Errorf("Cannot look up releases without a project")
+	}
+
+	releases, err := b.listReleases("")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return b.undeclaredReleases(releases), nil
+}
+
+// undeclaredReleases filters releases down to the ones UndeclaredReleases returns
+func (b *HelmBuilder) undeclaredReleases(releases []*release.Release) []*ManagedRelease {
+	declared := map[string]bool{}
+
+	for _, d := range b.deployments {
+		declared[d.Name+"/"+b.namespace(d)] = true
+	}
+
+	var undeclared []*ManagedRelease
+
+	for _, rel := range releases {
+		if !deployedBy(rel.Chart, b.project, b.CommandOpts.Environment, b.CommandOpts.Namespace) {
+			continue
+		}
+
+		if rel.Info.Status == release.StatusUninstalled || declared[rel.Name+"/"+rel.Namespace] {
+			continue
+		}
+
+		undeclared = append(undeclared, managedRelease(rel))
+	}
+
+	return undeclared
+}
+
+// NamespaceReleases returns the releases sym installed in a namespace for any project
+func (b *HelmBuilder) NamespaceReleases(namespace string) ([]*ManagedRelease, error) {
+	releases, err := b.listReleases(namespace)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var managed []*ManagedRelease
+
+	for _, rel := range releases {
+		if rel.Chart == nil || rel.Chart.Metadata == nil || rel.Chart.Metadata.Annotations[PROJECT_ANNOTATION] == "" {
+			continue
+		}
+
+		if rel.Info.Status == release.StatusUninstalled {
+			continue
+		}
+
+		managed = append(managed, managedRelease(rel))
+	}
+
+	return managed, nil
+}
+
+// listReleases returns the releases of a namespace in any state, an empty namespace lists all namespaces
+func (b *HelmBuilder) listReleases(namespace string) ([]*release.Release, error) {
+	config, err := b.actionConfig(namespace)
+
+	if err != nil {
+		return nil, err
+	}
+
+	list := action.NewList(config)
+	list.AllNamespaces = namespace == ""
+	list.All = true
+	list.SetStateMask()
+
+	releases, err := list.Run()
+
+	if err != nil {
+		return nil, &HelmError{Action: "list", Release: "*", Err: err}
+	}
+
+	return releases, nil
+}
+
+func managedRelease(rel *release.Release) *ManagedRelease {
+	return &ManagedRelease{
+		Name:      rel.Name,
+		Namespace: rel.Namespace,
+		Chart:     fmt.Sprintf("%s-%s", rel.Chart.Metadata.Name, rel.Chart.Metadata.Version),
+		Revision:  rel.Version,
+		Status:    rel.Info.Status.String(),
+	}
+}
+
+// Prune uninstalls releases found by UndeclaredReleases
+func (b *HelmBuilder) Prune(releases []*ManagedRelease) ([]*DestroyResult, error) {
+	var results []*DestroyResult
+
+	for _, rel := range releases {
+		config, err := b.actionConfig(rel.Namespace)
+
+		if err != nil {
+			return results, err
+		}
+
+		b.CommandOpts.Logger.Info().Msgf("Uninstalling Helm release %s from namespace %s", rel.Name, rel.Namespace)
+
+		status := DESTROY_STATUS_REMOVED
+
+		err = uninstallRelease(config, rel.Name)
+
+		if errors.Is(err, ErrReleaseNotFound) {
+			status = DESTROY_STATUS_NOT_PRESENT
+		} else if err != nil {
+			return results, err
+		}
+
+		results = append(results, &DestroyResult{"helm", rel.Name, rel.Namespace, status})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,106 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestDeployedBy(t *testing.T) {
+	annotations := func(project string, environment string, namespace *string) map[string]string {
+		values := map[string]string{PROJECT_ANNOTATION: project, ENVIRONMENT_ANNOTATION: environment}
+
+		if namespace != nil {
+			values[NAMESPACE_ANNOTATION] = *namespace
+		}
+
+		return values
+	}
+
+	empty := ""
+	staging := "staging"
+
+	tests := []struct {
+		name        string
+		chart       *chart.Chart
+		environment string
+		namespace   string
+		expected    bool
+	}{
+		{name: "same project", chart: &chart.Chart{Metadata: &chart.Metadata{Annotations: annotations("web", "", &empty)}}, expected: true},
+		{name: "same environment and namespace", chart: &chart.Chart{Metadata: &chart.Metadata{Annotations: annotations("web", "production", &staging)}}, environment: "production", namespace: "staging", expected: true},
+		{name: "other project", chart: &chart.Chart{Metadata: &chart.Metadata{Annotations: annotations("api", "", &empty)}}},
+		{name: "other environment", chart: &chart.Chart{Metadata: &chart.Metadata{Annotations: annotations("web", "staging", &empty)}}, environment: "production"},
+		{name: "environment deploy of a plain deploy", chart: &chart.Chart{Metadata: &chart.Metadata{Annotations: annotations("web", "", &empty)}}, environment: "production"},
+		{name: "other namespace", chart: &chart.Chart{Metadata: &chart.Metadata{Annotations: annotations("web", "", &staging)}}, namespace: "preview"},
+		{name: "missing namespace annotation", chart: &chart.Chart{Metadata: &chart.Metadata{Annotations: annotations("web", "", nil)}}},
+		{name: "no annotations", chart: &chart.Chart{Metadata: &chart.Metadata{}}},
+		{name: "no metadata", chart: &chart.Chart{}},
+		{name: "no chart"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if deployed := deployedBy(test.chart, "web", test.environment, test.namespace); deployed != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, deployed)
+			}
+		})
+	}
+}
+
+func TestUndeclaredReleases(t *testing.T) {
+	owned := func(environment string, namespace string) map[string]string {
+		return map[string]string{PROJECT_ANNOTATION: "web", ENVIRONMENT_ANNOTATION: environment, NAMESPACE_ANNOTATION: namespace}
+	}
+
+	uninstalled := testRelease("old", "default", owned("production", ""))
+	uninstalled.Info.Status = release.StatusUninstalled
+
+	releases := []*release.Release{
+		testRelease("api", "default", owned("production", "")),
+		testRelease("worker", "default", owned("production", "")),
+		testRelease("api", "jobs", owned("production", "")),
+		testRelease("cron", "default", owned("staging", "")),
+		testRelease("cron", "preview", owned("production", "preview")),
+		testRelease("cache", "default", map[string]string{PROJECT_ANNOTATION: "web", ENVIRONMENT_ANNOTATION: "production"}),
+		testRelease("redis", "default", map[string]string{PROJECT_ANNOTATION: "other", ENVIRONMENT_ANNOTATION: "production", NAMESPACE_ANNOTATION: ""}),
+		testRelease("ingress", "default", nil),
+		uninstalled,
+	}
+
+	tests := []struct {
+		name        string
+		environment string
+		namespace   string
+		expected    []string
+	}{
+		{name: "production", environment: "production", expected: []string{"default/worker", "jobs/api"}},
+		{name: "staging", environment: "staging", expected: []string{"default/cron"}},
+		{name: "namespace override", environment: "production", namespace: "preview", expected: []string{"preview/cron"}},
+		{name: "plain deploy", expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &HelmBuilder{
+				project:     "web",
+				deployments: []HelmDeployment{{Name: "api", Namespace: "default"}},
+				CommandOpts: &symcommand.CommandOpts{Logger: zerolog.Nop(), Environment: test.environment, Namespace: test.namespace},
+			}
+
+			var undeclared []string
+
+			for _, rel := range b.undeclaredReleases(releases) {
+				undeclared = append(undeclared, rel.Namespace+"/"+rel.Name)
+			}
+
+			if !reflect.DeepEqual(undeclared, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, undeclared)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// ErrReleaseNotFound is returned when a release is not installed in the namespace
+var ErrReleaseNotFound = driver.ErrReleaseNotFound
+
+// HelmError is returned when a Helm action fails for a release
+type HelmError struct {
+	Action  string
+	Release string
+	Err     error
+}
+
+func (e *HelmError) Error() string {
+	return fmt.Sprintf("Helm %s failed for %s: %v", e.Action, e.Release, e.Err)
+}
+
+func (e *HelmError) Unwrap() error {
+	return e.Err
+}
+
+// Release returns the latest revision of a release in the namespace of its deployment
+func (b *HelmBuilder) Release(name string) (*release.Release, error) {
+	config, err := b.actionConfig(b.releaseNamespace(name))
+
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := config.Releases.Last(name)
+
+	if err != nil {
+		return nil, &HelmError{Action: "status", Release: name, Err: err}
+	}
+
+	return rel, nil
+}
+
+// History returns all stored revisions of a release, oldest first
+func (b *HelmBuilder) History(name string) ([]*release.Release, error) {
+	config, err := b.actionConfig(b.releaseNamespace(name))
+
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := action.NewHistory(config).Run(name)
+
+	if err != nil {
+		return nil, &HelmError{Action: "history", Release: name, Err: err}
+	}
+
+	return history, nil
+}
+
+// ReleaseRevision is the revision of a release at a point in time
+type ReleaseRevision struct {
+	Namespace string `json:"namespace"`
+	Revision  int    `json:"revision"`
+}
+
+// Revisions returns the current revision of every installed release
+func (b *HelmBuilder) Revisions() (map[string]*ReleaseRevision, error) {
+	revisions := map[string]*ReleaseRevision{}
+
+	for _, d := range b.deployments {
+		namespace := b.namespace(d)
+
+		config, err := b.actionConfig(namespace)
+
+		if err != nil {
+			return nil, err
+		}
+
+		revision, err := releaseRevision(config, d.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if revision > 0 {
+			revisions[d.Name] = &ReleaseRevision{namespace, revision}
+		}
+	}
+
+	return revisions, nil
+}
+
+// RollbackRelease rolls a release back to the given revision and returns the revision it had before
+func (b *HelmBuilder) RollbackRelease(name string, revision *ReleaseRevision) (int, error) {
+	config, err := b.actionConfig(revision.Namespace)
+
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := releaseRevision(config, name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if current == 0 {
+		return 0, &HelmError{Action: "rollback", Release: name, Err: ErrReleaseNotFound}
+	}
+
+	if current == revision.Revision {
+		return current, nil
+	}
+
+	return current, rollbackRelease(config, name, revision.Revision, b.releaseTimeout(name))
+}
+
+// Deployments returns the Helm deployments of the builder
+func (b *HelmBuilder) Deployments() []HelmDeployment {
+	return b.deployments
+}
+
+// Namespaces returns the namespaces the releases of the builder are installed in
+func (b *HelmBuilder) Namespaces() []string {
+	var namespaces []string
+
+	for _, d := range b.deployments {
+		namespaces = append(namespaces, b.namespace(d))
+	}
+
+	return namespaces
+}
+
+// namespace returns the namespace of a deployment, falling back to --namespace
+func (b *HelmBuilder) namespace(d HelmDeployment) string {
+	if d.Namespace != "" {
+		return d.Namespace
+	}
+
+	return b.CommandOpts.Namespace
+}
+
+func (b *HelmBuilder) releaseNamespace(name string) string {
+	for _, d := range b.deployments {
+		if d.Name == name {
+			return b.namespace(d)
+		}
+	}
+
+	return b.CommandOpts.Namespace
+}
+
+// releaseTimeout returns the timeout of a declared release, or the Helm default for releases that are
+// no longer in sym.yaml
+func (b *HelmBuilder) releaseTimeout(name string) time.Duration {
+	for _, d := range b.deployments {
+		if d.Name != name {
+			continue
+		}
+
+		if timeout, err := d.TimeoutDuration(); err == nil {
+			return timeout
+		}
+	}
+
+	return DEFAULT_HELM_TIMEOUT
+}
+
+// releaseRevision returns the current revision of a release or 0 if it is not installed
+func releaseRevision(config *action.Configuration, name string) (int, error) {
+	rel, err := config.Releases.Last(name)
+
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, &HelmError{Action: "status", Release: name, Err: err}
+	}
+
+	return rel.Version, nil
+}
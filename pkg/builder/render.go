@@ -0,0 +1,106 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/action"
+)
+
+// Manifest is the rendered output of a single Helm release or kustomization. Previous holds the
+// manifest of the installed release, so objects removed from the chart can be detected.
+type Manifest struct {
+	Builder   string
+	Name      string
+	Namespace string
+	Content   []byte
+	Previous  []byte
+}
+
+// Render renders every release with its resolved values against the cluster, without installing anything.
+// Charts are resolved by Build, which has to run first.
+func (b *HelmBuilder) Render() ([]*Manifest, error) {
+	var manifests []*Manifest
+
+	for _, wave := range b.waves {
+		for _, d := range wave {
+			manifest, err := b.render(d)
+
+			if err != nil {
+				return nil, err
+			}
+
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	return manifests, nil
+}
+
+func (b *HelmBuilder) render(d HelmDeployment) (*Manifest, error) {
+	namespace := b.namespace(d)
+
+	config, err := b.actionConfig(namespace)
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{Builder: "helm", Name: d.Name, Namespace: namespace}
+
+	current, err := config.Releases.Last(d.Name)
+
+	if err != nil && !errors.Is(err, ErrReleaseNotFound) {
+		return nil, &HelmError{Action: "status", Release: d.Name, Err: err}
+	} else if err == nil {
+		manifest.Previous = []byte(current.Manifest)
+	}
+
+	helmChart, err := b.loadChart(config, d)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := b.values(d)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// a dry run renders against the capabilities of the cluster but never changes it
+	install := action.NewInstall(config)
+	install.ReleaseName = d.Name
+	install.Namespace = namespace
+	install.DryRun = true
+	install.Replace = true
+	install.IsUpgrade = current != nil
+	install.PostRenderer = &projectLabeler{b.project}
+
+	rel, err := install.Run(helmChart, values)
+
+	if err != nil {
+		return nil, &HelmError{Action: "template", Release: d.Name, Err: err}
+	}
+
+	manifest.Content = []byte(rel.Manifest)
+
+	return manifest, nil
+}
+
+// Render returns the kustomizations built by Build
+func (b *KustomizeBuilder) Render() ([]*Manifest, error) {
+	var manifests []*Manifest
+
+	for _, d := range b.deployments {
+		content, ok := b.manifests[d.Path]
+
+		if !ok {
+			return nil, fmt.Errorf("Kustomization %s has not been built yet", d.Path)
+		}
+
+		manifests = append(manifests, &Manifest{Builder: "kustomize", Name: d.Path, Namespace: b.namespace(d), Content: content})
+	}
+
+	return manifests, nil
+}
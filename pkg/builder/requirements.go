@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
+
+	"github.com/Masterminds/semver/v3"
 )
 
 type Requirement interface {
 	Value() string
 	Check() bool
 	Failed() error
+	Hint() string
 }
 
 type CommandRequirement struct {
@@ -31,6 +35,10 @@ func (r *CommandRequirement) Failed() error {
 	return fmt.Errorf("Requirement not met. Command %s not present on this system", r.Value())
 }
 
+func (r *CommandRequirement) Hint() string {
+	return fmt.Sprintf("Install %s and make sure it is in your PATH", r.Command)
+}
+
 type FileRequirement struct {
 	Path string
 }
@@ -47,6 +55,78 @@ func (r *FileRequirement) Failed() error {
 	return fmt.Errorf("Requirement not met. File %s not found", r.Value())
 }
 
+func (r *FileRequirement) Hint() string {
+	return fmt.Sprintf("Create %s or fix its path in sym.yaml", r.Path)
+}
+
+// VersionRequirement checks that a command is installed in a version matching a semver constraint such as
+// ">= 1.14". The version is taken from the first version number in the output of the command run with Args.
+type VersionRequirement struct {
+	Command    string
+	Args       []string
+	Constraint string
+
+	version string
+	err     error
+}
+
+var versionPattern = regexp.MustCompile(`v?(\d+)\.(\d+)(\.\d+)?`)
+
+func (r *VersionRequirement) Value() string {
+	return fmt.Sprintf("%s %s", r.Command, r.Constraint)
+}
+
+func (r *VersionRequirement) Check() bool {
+	output, err := exec.Command(r.Command, r.Args...).CombinedOutput()
+
+	if err != nil {
+		r.err = fmt.Errorf("Command %s not present on this system", r.Command)
+		return false
+	}
+
+	match := versionPattern.FindString(string(output))
+
+	if match == "" {
+		r.err = fmt.Errorf("Could not determine the version of %s", r.Command)
+		return false
+	}
+
+	r.version = match
+
+	version, err := semver.NewVersion(match)
+
+	if err != nil {
+		r.err = err
+		return false
+	}
+
+	constraint, err := semver.NewConstraint(r.Constraint)
+
+	if err != nil {
+		r.err = fmt.Errorf("Invalid version constraint %q for %s", r.Constraint, r.Command)
+		return false
+	}
+
+	return constraint.Check(version)
+}
+
+func (r *VersionRequirement) Failed() error {
+	if r.err != nil {
+		return fmt.Errorf("Requirement not met. %v", r.err)
+	}
+
+	return fmt.Errorf("Requirement not met. %s %s found, %s required", r.Command, r.version, r.Constraint)
+}
+
+func (r *VersionRequirement) Hint() string {
+	return fmt.Sprintf("Install %s %s and make sure it is in your PATH", r.Command, r.Constraint)
+}
+
+// Requirements returns everything a builder needs to run, without checking it
+func Requirements(b Builder) ([]Requirement, error) {
+	return b.requirements()
+}
+
 func meetsRequirements(b Builder) error {
 	requirements, err := b.requirements()
 
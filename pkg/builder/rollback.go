@@ -0,0 +1,104 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// touchedRelease is a release changed by the current deploy, revision 0 means it was newly installed
+type touchedRelease struct {
+	name      string
+	namespace string
+	revision  int
+	timeout   time.Duration
+}
+
+func (b *HelmBuilder) touch(name string, namespace string, revision int, timeout time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.touched = append(b.touched, &touchedRelease{name, namespace, revision, timeout})
+}
+
+// Rollback reverts every release touched by the last deploy, newest first. Upgraded releases are rolled
+// back to their previous revision and new releases are uninstalled. The touched releases are forgotten
+// afterwards, so a second call does nothing.
+func (b *HelmBuilder) Rollback() {
+	if len(b.touched) == 0 {
+		return
+	}
+
+	defer func() {
+		b.touched = nil
+	}()
+
+	b.CommandOpts.Logger.Warn().Msgf("Deploy failed, reverting %d release(s)", len(b.touched))
+
+	var summary []string
+
+	for i := len(b.touched) - 1; i >= 0; i-- {
+		release := b.touched[i]
+
+		config, err := b.actionConfig(release.namespace)
+
+		if err != nil {
+			b.CommandOpts.Logger.Error().Msgf("Could not revert release %s: %v", release.name, err)
+			summary = append(summary, fmt.Sprintf("%s: revert failed", release.name))
+			continue
+		}
+
+		var action string
+
+		if release.revision == 0 {
+			err = uninstallRelease(config, release.name)
+			action = "uninstalled"
+		} else {
+			err = rollbackRelease(config, release.name, release.revision, release.timeout)
+			action = fmt.Sprintf("rolled back to revision %d", release.revision)
+		}
+
+		// an atomic release may have been reverted by Helm already
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			action = "already removed"
+		} else if err != nil {
+			b.CommandOpts.Logger.Error().Msgf("Could not revert release %s: %v", release.name, err)
+			summary = append(summary, fmt.Sprintf("%s: revert failed", release.name))
+			continue
+		}
+
+		summary = append(summary, fmt.Sprintf("%s: %s", release.name, action))
+	}
+
+	b.CommandOpts.Logger.Warn().Msgf("Reverted releases:\n  %s", strings.Join(summary, "\n  "))
+}
+
+// rollbackRelease waits for the rollback within the timeout of the deployment, like the install did
+func rollbackRelease(config *action.Configuration, name string, revision int, timeout time.Duration) error {
+	rollback := action.NewRollback(config)
+	rollback.Version = revision
+	rollback.Wait = true
+	rollback.Timeout = timeout
+
+	err := rollback.Run(name)
+
+	if err != nil {
+		return &HelmError{Action: "rollback", Release: name, Err: err}
+	}
+
+	return nil
+}
+
+func uninstallRelease(config *action.Configuration, name string) error {
+	_, err := action.NewUninstall(config).Run(name)
+
+	if err != nil {
+		return &HelmError{Action: "uninstall", Release: name, Err: err}
+	}
+
+	return nil
+}
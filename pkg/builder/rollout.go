@@ -0,0 +1,163 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	HELM_RELEASE_NAME_ANNOTATION      = "meta.helm.sh/release-name"
+	HELM_RELEASE_NAMESPACE_ANNOTATION = "meta.helm.sh/release-namespace"
+	DEFAULT_HELM_TIMEOUT              = 5 * time.Minute
+)
+
+var rolloutInterval = 5 * time.Second
+
+// waitForRelease polls the Deployments, StatefulSets and DaemonSets owned by a release until they are rolled out
+func waitForRelease(clientset kubernetes.Interface, namespace string, release string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		pending, err := pendingWorkloads(clientset, namespace, release)
+
+		if err != nil {
+			return err
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timeout waiting for release %s to become ready: %s", release, strings.Join(pending, ", "))
+		}
+
+		time.Sleep(rolloutInterval)
+	}
+}
+
+// pendingWorkloads returns the workloads of a release that are not rolled out yet
+func pendingWorkloads(clientset kubernetes.Interface, namespace string, release string) ([]string, error) {
+	var pending []string
+	ctx := context.TODO()
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, deployment := range deployments.Items {
+		if !ownedByRelease(deployment.ObjectMeta, namespace, release) {
+			continue
+		}
+
+		ready, err := deploymentReady(&deployment)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !ready {
+			pending = append(pending, "deployment/"+deployment.Name)
+		}
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statefulSet := range statefulSets.Items {
+		if ownedByRelease(statefulSet.ObjectMeta, namespace, release) && !statefulSetReady(&statefulSet) {
+			pending = append(pending, "statefulset/"+statefulSet.Name)
+		}
+	}
+
+	daemonSets, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, daemonSet := range daemonSets.Items {
+		if ownedByRelease(daemonSet.ObjectMeta, namespace, release) && !daemonSetReady(&daemonSet) {
+			pending = append(pending, "daemonset/"+daemonSet.Name)
+		}
+	}
+
+	return pending, nil
+}
+
+func ownedByRelease(meta metav1.ObjectMeta, namespace string, release string) bool {
+	return meta.Annotations[HELM_RELEASE_NAME_ANNOTATION] == release && meta.Annotations[HELM_RELEASE_NAMESPACE_ANNOTATION] == namespace
+}
+
+// deploymentReady follows the checks of kubectl rollout status
+func deploymentReady(deployment *appsv1.Deployment) (bool, error) {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false, nil
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return false, fmt.Errorf("Deployment %s exceeded its progress deadline", deployment.Name)
+		}
+	}
+
+	replicas := int32(1)
+
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.UpdatedReplicas >= replicas &&
+		deployment.Status.Replicas <= deployment.Status.UpdatedReplicas &&
+		deployment.Status.AvailableReplicas >= deployment.Status.UpdatedReplicas, nil
+}
+
+func statefulSetReady(statefulSet *appsv1.StatefulSet) bool {
+	if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		return false
+	}
+
+	replicas := int32(1)
+
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	if statefulSet.Status.ReadyReplicas < replicas {
+		return false
+	}
+
+	if statefulSet.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
+		return true
+	}
+
+	if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		return statefulSet.Status.UpdatedReplicas >= replicas-*rollingUpdate.Partition
+	}
+
+	return statefulSet.Status.UpdateRevision == statefulSet.Status.CurrentRevision
+}
+
+func daemonSetReady(daemonSet *appsv1.DaemonSet) bool {
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return false
+	}
+
+	if daemonSet.Spec.UpdateStrategy.Type == appsv1.RollingUpdateDaemonSetStrategyType &&
+		daemonSet.Status.UpdatedNumberScheduled < daemonSet.Status.DesiredNumberScheduled {
+		return false
+	}
+
+	return daemonSet.Status.NumberAvailable >= daemonSet.Status.DesiredNumberScheduled
+}
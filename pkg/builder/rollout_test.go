@@ -0,0 +1,189 @@
+package builder
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func int32Pointer(value int32) *int32 {
+	return &value
+}
+
+func TestDeploymentReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas *int32
+		status   appsv1.DeploymentStatus
+		ready    bool
+		err      string
+	}{
+		{name: "rolled out", replicas: int32Pointer(2), status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}, ready: true},
+		{name: "default of one replica", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}, ready: true},
+		{name: "scaled to zero", replicas: int32Pointer(0), status: appsv1.DeploymentStatus{ObservedGeneration: 2}, ready: true},
+		{name: "generation not observed", replicas: int32Pointer(1), status: appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}},
+		{name: "replicas not updated", replicas: int32Pointer(2), status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 2}},
+		{name: "old replicas remaining", replicas: int32Pointer(2), status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 3}},
+		{name: "updated replicas unavailable", replicas: int32Pointer(2), status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1}},
+		{
+			name:     "progress deadline exceeded",
+			replicas: int32Pointer(1),
+			status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				Conditions:         []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"}},
+			},
+			err: "Deployment web exceeded its progress deadline",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: test.replicas},
+				Status:     test.status,
+			}
+
+			ready, err := deploymentReady(deployment)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ready != test.ready {
+				t.Errorf("expected ready %v, got %v", test.ready, ready)
+			}
+		})
+	}
+}
+
+func TestStatefulSetReady(t *testing.T) {
+	rollingUpdate := appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}
+	partitioned := appsv1.StatefulSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: int32Pointer(1)},
+	}
+
+	tests := []struct {
+		name     string
+		strategy appsv1.StatefulSetUpdateStrategy
+		status   appsv1.StatefulSetStatus
+		ready    bool
+	}{
+		{name: "rolled out", strategy: rollingUpdate, status: appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, CurrentRevision: "b", UpdateRevision: "b"}, ready: true},
+		{name: "generation not observed", strategy: rollingUpdate, status: appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 3, CurrentRevision: "b", UpdateRevision: "b"}},
+		{name: "replicas not ready", strategy: rollingUpdate, status: appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 2, CurrentRevision: "b", UpdateRevision: "b"}},
+		{name: "revision rolling out", strategy: rollingUpdate, status: appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, CurrentRevision: "a", UpdateRevision: "b"}},
+		{name: "partition updated", strategy: partitioned, status: appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 2, CurrentRevision: "a", UpdateRevision: "b"}, ready: true},
+		{name: "partition not updated", strategy: partitioned, status: appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "a", UpdateRevision: "b"}},
+		{name: "on delete", strategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}, status: appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, CurrentRevision: "a", UpdateRevision: "b"}, ready: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: int32Pointer(3), UpdateStrategy: test.strategy},
+				Status:     test.status,
+			}
+
+			if ready := statefulSetReady(statefulSet); ready != test.ready {
+				t.Errorf("expected ready %v, got %v", test.ready, ready)
+			}
+		})
+	}
+}
+
+func TestDaemonSetReady(t *testing.T) {
+	rollingUpdate := appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType}
+
+	tests := []struct {
+		name     string
+		strategy appsv1.DaemonSetUpdateStrategy
+		status   appsv1.DaemonSetStatus
+		ready    bool
+	}{
+		{name: "rolled out", strategy: rollingUpdate, status: appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3}, ready: true},
+		{name: "generation not observed", strategy: rollingUpdate, status: appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3}},
+		{name: "nodes not updated", strategy: rollingUpdate, status: appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 2, NumberAvailable: 3}},
+		{name: "pods unavailable", strategy: rollingUpdate, status: appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2}},
+		{name: "on delete", strategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}, status: appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 0, NumberAvailable: 3}, ready: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			daemonSet := &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Generation: 2},
+				Spec:       appsv1.DaemonSetSpec{UpdateStrategy: test.strategy},
+				Status:     test.status,
+			}
+
+			if ready := daemonSetReady(daemonSet); ready != test.ready {
+				t.Errorf("expected ready %v, got %v", test.ready, ready)
+			}
+		})
+	}
+}
+
+func TestPendingWorkloads(t *testing.T) {
+	owned := func(release string, namespace string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:   "default",
+			Generation:  1,
+			Annotations: map[string]string{HELM_RELEASE_NAME_ANNOTATION: release, HELM_RELEASE_NAMESPACE_ANNOTATION: namespace},
+		}
+	}
+
+	named := func(meta metav1.ObjectMeta, name string) metav1.ObjectMeta {
+		meta.Name = name
+		return meta
+	}
+
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: named(owned("web", "default"), "ready")},
+		&appsv1.Deployment{ObjectMeta: named(owned("web", "default"), "rolling")},
+		&appsv1.Deployment{ObjectMeta: named(owned("api", "default"), "other-release")},
+		&appsv1.Deployment{ObjectMeta: named(owned("web", "staging"), "other-namespace")},
+		&appsv1.StatefulSet{ObjectMeta: named(owned("web", "default"), "db"), Spec: appsv1.StatefulSetSpec{Replicas: int32Pointer(1)}},
+		&appsv1.DaemonSet{ObjectMeta: named(owned("web", "default"), "agent"), Status: appsv1.DaemonSetStatus{ObservedGeneration: 1}},
+	)
+
+	ready, err := clientset.AppsV1().Deployments("default").Get(context.TODO(), "ready", metav1.GetOptions{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ready.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+
+	_, err = clientset.AppsV1().Deployments("default").UpdateStatus(context.TODO(), ready, metav1.UpdateOptions{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := pendingWorkloads(clientset, "default", "web")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"deployment/rolling", "statefulset/db"}
+
+	if !reflect.DeepEqual(pending, expected) {
+		t.Errorf("expected %v, got %v", expected, pending)
+	}
+}
@@ -0,0 +1,229 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/symbiosis-cloud/cli/pkg/manifest"
+	"github.com/symbiosis-cloud/cli/pkg/util"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	STATUS_NOT_DEPLOYED       = "not deployed"
+	STATUS_DEPLOYED           = "deployed"
+	STATUS_PARTIALLY_DEPLOYED = "partially deployed"
+)
+
+// DeploymentStatus describes the state of a Helm release or kustomization in the cluster. Ready and Desired
+// count the replicas of its Deployments, StatefulSets and DaemonSets.
+type DeploymentStatus struct {
+	Builder      string     `json:"builder"`
+	Name         string     `json:"name"`
+	Namespace    string     `json:"namespace"`
+	Revision     int        `json:"revision,omitempty"`
+	Chart        string     `json:"chart,omitempty"`
+	LastDeployed *time.Time `json:"lastDeployed,omitempty"`
+	Status       string     `json:"status"`
+	Deployed     bool       `json:"deployed"`
+	Ready        int32      `json:"ready"`
+	Desired      int32      `json:"desired"`
+}
+
+// Status returns the status of every release in deployment order
+func (b *HelmBuilder) Status() ([]*DeploymentStatus, error) {
+	clientset, err := util.GetKubernetesClient(b.GetIdentity().KubeConfigPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []*DeploymentStatus
+
+	for _, wave := range b.waves {
+		for _, d := range wave {
+			namespace := b.namespace(d)
+			status := &DeploymentStatus{Builder: "helm", Name: d.Name, Namespace: namespace, Status: STATUS_NOT_DEPLOYED}
+
+			statuses = append(statuses, status)
+
+			config, err := b.actionConfig(namespace)
+
+			if err != nil {
+				return nil, err
+			}
+
+			rel, err := config.Releases.Last(d.Name)
+
+			if errors.Is(err, ErrReleaseNotFound) {
+				continue
+			} else if err != nil {
+				return nil, &HelmError{Action: "status", Release: d.Name, Err: err}
+			}
+
+			lastDeployed := rel.Info.LastDeployed.Time
+
+			status.Revision = rel.Version
+			status.Chart = fmt.Sprintf("%s-%s", rel.Chart.Metadata.Name, rel.Chart.Metadata.Version)
+			status.LastDeployed = &lastDeployed
+			status.Status = rel.Info.Status.String()
+			status.Deployed = true
+
+			objects, err := manifest.Parse([]byte(rel.Manifest))
+
+			if err != nil {
+				return nil, err
+			}
+
+			status.Ready, status.Desired, err = workloadReplicas(clientset, objects, namespace)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return statuses, nil
+}
+
+// Status returns the status of every kustomization, based on how many of its objects exist
+func (b *KustomizeBuilder) Status() ([]*DeploymentStatus, error) {
+	clientset, err := util.GetKubernetesClient(b.GetIdentity().KubeConfigPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := manifest.NewClient(b.GetIdentity().KubeConfigPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []*DeploymentStatus
+
+	for _, d := range b.deployments {
+		namespace := b.namespace(d)
+
+		content, err := b.manifest(d)
+
+		if err != nil {
+			return nil, err
+		}
+
+		objects, err := manifest.Parse(content)
+
+		if err != nil {
+			return nil, err
+		}
+
+		existing := 0
+
+		for _, object := range objects {
+			live, err := client.Get(context.TODO(), object, namespace)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if live != nil {
+				existing++
+			}
+		}
+
+		status := &DeploymentStatus{Builder: "kustomize", Name: d.Path, Namespace: namespace}
+
+		switch {
+		case existing == 0:
+			status.Status = STATUS_NOT_DEPLOYED
+		case existing < len(objects):
+			status.Status = fmt.Sprintf("%s (%d/%d objects)", STATUS_PARTIALLY_DEPLOYED, existing, len(objects))
+			status.Deployed = true
+		default:
+			status.Status = STATUS_DEPLOYED
+			status.Deployed = true
+		}
+
+		status.Ready, status.Desired, err = workloadReplicas(clientset, objects, namespace)
+
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// workloadReplicas sums the ready and desired replicas of the Deployments, StatefulSets and DaemonSets among
+// objects. Missing workloads count with the replicas of their manifest.
+func workloadReplicas(clientset kubernetes.Interface, objects []*unstructured.Unstructured, namespace string) (int32, int32, error) {
+	var ready, desired int32
+
+	ctx := context.TODO()
+	apps := clientset.AppsV1()
+
+	for _, object := range objects {
+		objectNamespace := object.GetNamespace()
+
+		if objectNamespace == "" {
+			objectNamespace = namespace
+		}
+
+		var err error
+
+		switch object.GetKind() {
+		case "Deployment":
+			deployment, getErr := apps.Deployments(objectNamespace).Get(ctx, object.GetName(), metav1.GetOptions{})
+
+			if err = getErr; err == nil {
+				desired += replicas(deployment.Spec.Replicas)
+				ready += deployment.Status.ReadyReplicas
+			}
+		case "StatefulSet":
+			statefulSet, getErr := apps.StatefulSets(objectNamespace).Get(ctx, object.GetName(), metav1.GetOptions{})
+
+			if err = getErr; err == nil {
+				desired += replicas(statefulSet.Spec.Replicas)
+				ready += statefulSet.Status.ReadyReplicas
+			}
+		case "DaemonSet":
+			daemonSet, getErr := apps.DaemonSets(objectNamespace).Get(ctx, object.GetName(), metav1.GetOptions{})
+
+			if err = getErr; err == nil {
+				desired += daemonSet.Status.DesiredNumberScheduled
+				ready += daemonSet.Status.NumberReady
+			}
+		default:
+			continue
+		}
+
+		if apierrors.IsNotFound(err) {
+			count, found, _ := unstructured.NestedInt64(object.Object, "spec", "replicas")
+
+			if !found {
+				count = 1
+			}
+
+			desired += int32(count)
+		} else if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return ready, desired, nil
+}
+
+func replicas(count *int32) int32 {
+	if count == nil {
+		return 1
+	}
+
+	return *count
+}
@@ -0,0 +1,126 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NormalizeValues converts the map[interface{}]interface{} trees produced by yaml.v2 into
+// map[string]interface{} so values can be merged and serialised as JSON
+func NormalizeValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			normalized[fmt.Sprint(key)] = NormalizeValues(item)
+		}
+
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			normalized[key] = NormalizeValues(item)
+		}
+
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+
+		for i, item := range v {
+			normalized[i] = NormalizeValues(item)
+		}
+
+		return normalized
+	}
+
+	return value
+}
+
+// MergeValues deep merges src into dst the same way Helm merges values files: maps are merged
+// recursively, every other value in src replaces the one in dst
+func MergeValues(dst map[string]interface{}, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = map[string]interface{}{}
+	}
+
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+
+		if srcIsMap && dstIsMap {
+			dst[key] = MergeValues(dstMap, srcMap)
+		} else {
+			dst[key] = value
+		}
+	}
+
+	return dst
+}
+
+// ExpandValues turns top-level keys with dots, such as image.tag, into nested maps the way --set did before
+// values were passed as a file. Dots can be escaped as \. and nested keys are kept as they are, so annotation
+// names and similar keys deeper in the tree still work. Dotted keys are applied after the other keys.
+func ExpandValues(values map[string]interface{}) (map[string]interface{}, error) {
+	expanded := map[string]interface{}{}
+
+	var dotted []string
+
+	for key, value := range values {
+		if strings.Contains(strings.ReplaceAll(key, `\.`, ""), ".") {
+			dotted = append(dotted, key)
+			continue
+		}
+
+		expanded[strings.ReplaceAll(key, `\.`, ".")] = value
+	}
+
+	sort.Strings(dotted)
+
+	for _, key := range dotted {
+		path := splitValuesKey(key)
+		value := values[key]
+
+		for _, segment := range path {
+			if segment == "" {
+				return nil, fmt.Errorf("Invalid values key %s", key)
+			}
+		}
+
+		for i := len(path) - 1; i > 0; i-- {
+			value = map[string]interface{}{path[i]: value}
+		}
+
+		existing, ok := expanded[path[0]]
+
+		if _, isMap := existing.(map[string]interface{}); ok && existing != nil && !isMap {
+			return nil, fmt.Errorf("Values key %s conflicts with the value of %s", key, path[0])
+		}
+
+		expanded = MergeValues(expanded, map[string]interface{}{path[0]: value})
+	}
+
+	return expanded, nil
+}
+
+func splitValuesKey(key string) []string {
+	var path []string
+	var current strings.Builder
+
+	for i := 0; i < len(key); i++ {
+		switch {
+		case key[i] == '\\' && i+1 < len(key) && key[i+1] == '.':
+			current.WriteByte('.')
+			i++
+		case key[i] == '.':
+			path = append(path, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(key[i])
+		}
+	}
+
+	return append(path, current.String())
+}
@@ -0,0 +1,164 @@
+package builder
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+	}{
+		{name: "scalar", value: "value", expected: "value"},
+		{name: "nil", value: nil, expected: nil},
+		{
+			name:     "yaml.v2 map",
+			value:    map[interface{}]interface{}{"image": map[interface{}]interface{}{"tag": "1.0"}, 1: true},
+			expected: map[string]interface{}{"image": map[string]interface{}{"tag": "1.0"}, "1": true},
+		},
+		{
+			name:     "maps in lists",
+			value:    []interface{}{map[interface{}]interface{}{"name": "a"}, "b"},
+			expected: []interface{}{map[string]interface{}{"name": "a"}, "b"},
+		},
+		{
+			name:     "string map with nested yaml.v2 map",
+			value:    map[string]interface{}{"resources": map[interface{}]interface{}{"cpu": 1}},
+			expected: map[string]interface{}{"resources": map[string]interface{}{"cpu": 1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if normalized := NormalizeValues(test.value); !reflect.DeepEqual(normalized, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, normalized)
+			}
+		})
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		dst      map[string]interface{}
+		src      map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "nil destination",
+			dst:      nil,
+			src:      map[string]interface{}{"a": 1},
+			expected: map[string]interface{}{"a": 1},
+		},
+		{
+			name:     "nested maps are merged",
+			dst:      map[string]interface{}{"image": map[string]interface{}{"repository": "app", "tag": "1.0"}},
+			src:      map[string]interface{}{"image": map[string]interface{}{"tag": "2.0"}},
+			expected: map[string]interface{}{"image": map[string]interface{}{"repository": "app", "tag": "2.0"}},
+		},
+		{
+			name:     "lists are replaced",
+			dst:      map[string]interface{}{"args": []interface{}{"a", "b"}},
+			src:      map[string]interface{}{"args": []interface{}{"c"}},
+			expected: map[string]interface{}{"args": []interface{}{"c"}},
+		},
+		{
+			name:     "a scalar replaces a map",
+			dst:      map[string]interface{}{"ingress": map[string]interface{}{"enabled": true}},
+			src:      map[string]interface{}{"ingress": false},
+			expected: map[string]interface{}{"ingress": false},
+		},
+		{
+			name:     "a map replaces a scalar",
+			dst:      map[string]interface{}{"ingress": false},
+			src:      map[string]interface{}{"ingress": map[string]interface{}{"enabled": true}},
+			expected: map[string]interface{}{"ingress": map[string]interface{}{"enabled": true}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if merged := MergeValues(test.dst, test.src); !reflect.DeepEqual(merged, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, merged)
+			}
+		})
+	}
+}
+
+func TestExpandValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   map[string]interface{}
+		expected map[string]interface{}
+		err      string
+	}{
+		{
+			name:     "plain keys",
+			values:   map[string]interface{}{"replicas": 2},
+			expected: map[string]interface{}{"replicas": 2},
+		},
+		{
+			name:     "dotted key",
+			values:   map[string]interface{}{"image.tag": "1.0"},
+			expected: map[string]interface{}{"image": map[string]interface{}{"tag": "1.0"}},
+		},
+		{
+			name: "dotted key merges into a map",
+			values: map[string]interface{}{
+				"image":     map[string]interface{}{"repository": "app"},
+				"image.tag": "1.0",
+			},
+			expected: map[string]interface{}{"image": map[string]interface{}{"repository": "app", "tag": "1.0"}},
+		},
+		{
+			name:     "escaped dot",
+			values:   map[string]interface{}{`podAnnotations.prometheus\.io/scrape`: "true"},
+			expected: map[string]interface{}{"podAnnotations": map[string]interface{}{"prometheus.io/scrape": "true"}},
+		},
+		{
+			name:     "escaped top-level key",
+			values:   map[string]interface{}{`example\.com`: 1},
+			expected: map[string]interface{}{"example.com": 1},
+		},
+		{
+			name:     "nested keys are kept",
+			values:   map[string]interface{}{"annotations": map[string]interface{}{"example.com/team": "a"}},
+			expected: map[string]interface{}{"annotations": map[string]interface{}{"example.com/team": "a"}},
+		},
+		{
+			name:   "empty segment",
+			values: map[string]interface{}{"image..tag": "1.0"},
+			err:    "Invalid values key image..tag",
+		},
+		{
+			name:   "conflict with a scalar",
+			values: map[string]interface{}{"image": "app:1.0", "image.tag": "1.0"},
+			err:    "conflicts with the value of image",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expanded, err := ExpandValues(test.values)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(expanded, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, expanded)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package manifest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const REDACTED = "REDACTED"
+
+// serverFields are populated by the API server and never part of a desired object
+var serverFields = map[string]bool{
+	"status":                     true,
+	"metadata.uid":               true,
+	"metadata.resourceVersion":   true,
+	"metadata.generation":        true,
+	"metadata.creationTimestamp": true,
+	"metadata.deletionTimestamp": true,
+	"metadata.managedFields":     true,
+	"metadata.selfLink":          true,
+	"metadata.ownerReferences":   true,
+}
+
+// FieldDiff is a field whose live value differs from the desired value, a nil Live value means the field is missing
+type FieldDiff struct {
+	Path    string      `json:"path"`
+	Desired interface{} `json:"desired"`
+	Live    interface{} `json:"live"`
+}
+
+// Diff compares the fields set in desired with the live object. Fields only present in the live object are
+// defaults or set by controllers and are ignored, as are the fields populated by the API server.
+// Values of secrets are redacted.
+func Diff(desired *unstructured.Unstructured, live *unstructured.Unstructured) []*FieldDiff {
+	desiredObject := desired.Object
+	liveObject := live.Object
+
+	if desired.GetKind() == "Secret" {
+		desiredObject = secretData(desiredObject)
+	}
+
+	var diffs []*FieldDiff
+
+	compare("", desiredObject, liveObject, &diffs)
+
+	if desired.GetKind() == "Secret" {
+		for _, diff := range diffs {
+			if strings.HasPrefix(diff.Path, "data") {
+				diff.Desired, diff.Live = redact(diff.Desired), redact(diff.Live)
+			}
+		}
+	}
+
+	return diffs
+}
+
+// Paths returns the paths of the given diffs
+func Paths(diffs []*FieldDiff) []string {
+	paths := make([]string, len(diffs))
+
+	for i, diff := range diffs {
+		paths[i] = diff.Path
+	}
+
+	return paths
+}
+
+func compare(path string, desired interface{}, live interface{}, diffs *[]*FieldDiff) {
+	if serverFields[path] || desired == nil {
+		return
+	}
+
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+
+		if !ok {
+			*diffs = append(*diffs, &FieldDiff{path, desired, live})
+			return
+		}
+
+		keys := make([]string, 0, len(d))
+
+		for key := range d {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			compare(fieldPath(path, key), d[key], l[key], diffs)
+		}
+	case []interface{}:
+		l, ok := live.([]interface{})
+
+		if !ok || len(l) != len(d) {
+			*diffs = append(*diffs, &FieldDiff{path, desired, live})
+			return
+		}
+
+		for i := range d {
+			compare(fmt.Sprintf("%s[%d]", path, i), d[i], l[i], diffs)
+		}
+	default:
+		if !equalValues(path, desired, live) {
+			*diffs = append(*diffs, &FieldDiff{path, desired, live})
+		}
+	}
+}
+
+// quantityPath matches the fields holding resource quantities, which the API server stores in canonical form
+var quantityPath = regexp.MustCompile(`(^|\.)(limits|requests|hard|default|defaultRequest|min|max|maxLimitRequestRatio|overhead|capacity)(\.|\[)|\.sizeLimit$`)
+
+// equalValues compares scalars. Numbers are compared by value since decoded manifests and live objects use
+// different types, and a number written as a string, such as "1", equals the number. Resource quantities are
+// compared by amount, so cpu 0.5 equals the 500m stored by the API server.
+func equalValues(path string, desired interface{}, live interface{}) bool {
+	if reflect.DeepEqual(desired, live) {
+		return true
+	}
+
+	d, dok := number(desired)
+	l, lok := number(live)
+
+	if dok && lok {
+		return d == l
+	}
+
+	if !quantityPath.MatchString(path) {
+		return false
+	}
+
+	dq, dok := quantity(desired)
+	lq, lok := quantity(live)
+
+	return dok && lok && dq.Cmp(lq) == 0
+}
+
+func quantity(value interface{}) (resource.Quantity, bool) {
+	var s string
+
+	if n, ok := number(value); ok {
+		s = strconv.FormatFloat(n, 'f', -1, 64)
+	} else if v, ok := value.(string); ok {
+		s = v
+	} else {
+		return resource.Quantity{}, false
+	}
+
+	q, err := resource.ParseQuantity(s)
+
+	return q, err == nil
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return 0, false
+		}
+
+		return n, true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+func fieldPath(path string, key string) string {
+	if strings.ContainsAny(key, "./") {
+		key = fmt.Sprintf("[%q]", key)
+	} else if path != "" {
+		key = "." + key
+	}
+
+	return path + key
+}
+
+// secretData moves stringData into data the way the API server stores it
+func secretData(object map[string]interface{}) map[string]interface{} {
+	stringData, ok := object["stringData"].(map[string]interface{})
+
+	if !ok {
+		return object
+	}
+
+	normalized := make(map[string]interface{}, len(object))
+
+	for key, value := range object {
+		normalized[key] = value
+	}
+
+	data := map[string]interface{}{}
+
+	if existing, ok := object["data"].(map[string]interface{}); ok {
+		for key, value := range existing {
+			data[key] = value
+		}
+	}
+
+	for key, value := range stringData {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+	}
+
+	delete(normalized, "stringData")
+	normalized["data"] = data
+
+	return normalized
+}
+
+func redact(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return REDACTED
+}
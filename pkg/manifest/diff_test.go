@@ -0,0 +1,216 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  labels:
+    app.kubernetes.io/name: api
+spec:
+  replicas: %s
+  template:
+    spec:
+      containers:
+      - name: api
+        image: %s
+        env:
+        - name: CACHE_SIZE
+          value: %s
+        resources:
+          limits:
+            cpu: %s
+            memory: %s
+`
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+		live    string
+		paths   []string
+	}{
+		{
+			name:    "unchanged",
+			desired: deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi"),
+			live:    deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi"),
+		},
+		{
+			name:    "number written as a string",
+			desired: deploymentManifest(`"3"`, "app:1.0", `"1Gi"`, "1", "1Gi"),
+			live:    deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi"),
+		},
+		{
+			name:    "cpu as number and string",
+			desired: deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi"),
+			live:    deploymentManifest("3", "app:1.0", `"1Gi"`, `"1"`, "1Gi"),
+		},
+		{
+			name:    "canonical cpu quantity",
+			desired: deploymentManifest("3", "app:1.0", `"1Gi"`, "0.5", "1Gi"),
+			live:    deploymentManifest("3", "app:1.0", `"1Gi"`, "500m", "1Gi"),
+		},
+		{
+			name:    "canonical memory quantity",
+			desired: deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1024Mi"),
+			live:    deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi"),
+		},
+		{
+			name:    "changed quantity",
+			desired: deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "2Gi"),
+			live:    deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi"),
+			paths:   []string{"spec.template.spec.containers[0].resources.limits.memory"},
+		},
+		{
+			name:    "quantities are only compared by amount in resource fields",
+			desired: deploymentManifest("3", "app:1.0", `"1024Mi"`, "1", "1Gi"),
+			live:    deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi"),
+			paths:   []string{"spec.template.spec.containers[0].env[0].value"},
+		},
+		{
+			name:    "changed fields",
+			desired: deploymentManifest("2", "app:1.1", `"1Gi"`, "1", "1Gi"),
+			live:    deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi"),
+			paths:   []string{"spec.replicas", "spec.template.spec.containers[0].image"},
+		},
+		{
+			name:    "fields set by the server and controllers are ignored",
+			desired: deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi"),
+			live: deploymentManifest("3", "app:1.0", `"1Gi"`, "1", "1Gi") + `
+  strategy:
+    type: RollingUpdate
+status:
+  replicas: 3
+`,
+		},
+		{
+			name: "labels with dots",
+			desired: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  labels:
+    app.kubernetes.io/name: web
+`,
+			live: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  resourceVersion: "12"
+  labels:
+    app.kubernetes.io/name: api
+`,
+			paths: []string{`metadata.labels["app.kubernetes.io/name"]`},
+		},
+		{
+			name: "missing key",
+			desired: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: "1"
+  b: "2"
+`,
+			live: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  a: "1"
+`,
+			paths: []string{"data.b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diffs := Diff(parseOne(t, test.desired), parseOne(t, test.live))
+
+			paths := Paths(diffs)
+
+			if len(paths) == 0 && len(test.paths) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(paths, test.paths) {
+				t.Errorf("expected changed paths %v, got %v", test.paths, paths)
+			}
+		})
+	}
+}
+
+func TestDiffRedactsSecrets(t *testing.T) {
+	desired := parseOne(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+stringData:
+  password: new-password
+`)
+
+	live := parseOne(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+data:
+  password: b2xkLXBhc3N3b3Jk
+`)
+
+	diffs := Diff(desired, live)
+
+	if len(diffs) != 1 || diffs[0].Path != "data.password" {
+		t.Fatalf("expected data.password to change, got %v", Paths(diffs))
+	}
+
+	if diffs[0].Desired != REDACTED || diffs[0].Live != REDACTED {
+		t.Errorf("expected redacted values, got %v and %v", diffs[0].Desired, diffs[0].Live)
+	}
+
+	unchanged := parseOne(t, `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+data:
+  password: bmV3LXBhc3N3b3Jk
+`)
+
+	if diffs := Diff(desired, unchanged); len(diffs) != 0 {
+		t.Errorf("expected stringData to match the stored data, got %v", Paths(diffs))
+	}
+}
+
+func deploymentManifest(replicas string, image string, cacheSize string, cpu string, memory string) string {
+	return fmt.Sprintf(deployment, replicas, image, cacheSize, cpu, memory)
+}
+
+func parseOne(t *testing.T, content string) *unstructured.Unstructured {
+	t.Helper()
+
+	objects, err := Parse([]byte(content))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objects) != 1 {
+		t.Fatalf("expected one object, got %d", len(objects))
+	}
+
+	return objects[0]
+}
@@ -0,0 +1,161 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Parse splits a multi-document manifest into objects, empty documents are skipped
+func Parse(content []byte) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+
+	var objects []*unstructured.Unstructured
+
+	for {
+		var raw json.RawMessage
+
+		err := decoder.Decode(&raw)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("Could not parse manifest: %v", err)
+		}
+
+		trimmed := bytes.TrimSpace(raw)
+
+		if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+			continue
+		}
+
+		// unstructured decoding keeps integers as int64 like objects read from the API server
+		u := &unstructured.Unstructured{}
+
+		err = u.UnmarshalJSON(trimmed)
+
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse manifest: %v", err)
+		}
+
+		if u.GetName() == "" {
+			return nil, fmt.Errorf("Manifest contains a %s without a name", u.GetKind())
+		}
+
+		objects = append(objects, u)
+	}
+
+	return objects, nil
+}
+
+// Key identifies an object within a cluster
+func Key(object *unstructured.Unstructured) string {
+	gvk := object.GroupVersionKind()
+
+	return fmt.Sprintf("%s/%s/%s/%s", gvk.Group, gvk.Kind, object.GetNamespace(), object.GetName())
+}
+
+// Client reads live objects of any kind from a cluster
+type Client struct {
+	dynamic dynamic.Interface
+	mapper  *restmapper.DeferredDiscoveryRESTMapper
+}
+
+func NewClient(kubeConfig string) (*Client, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		dynamic: dynamicClient,
+		mapper:  restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+	}, nil
+}
+
+// Reset forgets the kinds discovered so far, so custom resources installed since are found
+func (c *Client) Reset() {
+	c.mapper.Reset()
+}
+
+// Normalize sets the namespace of a namespaced object without one to defaultNamespace and clears it for
+// cluster scoped objects, matching what the API server stores. Kinds unknown to the cluster are left as is.
+func (c *Client) Normalize(object *unstructured.Unstructured, defaultNamespace string) error {
+	_, err := c.resource(object, defaultNamespace)
+
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+
+	return err
+}
+
+// Get returns the live version of an object or nil if it does not exist
+func (c *Client) Get(ctx context.Context, object *unstructured.Unstructured, defaultNamespace string) (*unstructured.Unstructured, error) {
+	resource, err := c.resource(object, defaultNamespace)
+
+	// the kind is not installed in the cluster, such as a custom resource whose CRD is part of the same deploy
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	live, err := resource.Get(ctx, object.GetName(), metav1.GetOptions{})
+
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return live, nil
+}
+
+func (c *Client) resource(object *unstructured.Unstructured, defaultNamespace string) (dynamic.ResourceInterface, error) {
+	gvk := object.GroupVersionKind()
+
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		object.SetNamespace("")
+
+		return c.dynamic.Resource(mapping.Resource), nil
+	}
+
+	if object.GetNamespace() == "" {
+		object.SetNamespace(defaultNamespace)
+	}
+
+	return c.dynamic.Resource(mapping.Resource).Namespace(object.GetNamespace()), nil
+}
@@ -0,0 +1,187 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/symbiosis-cloud/cli/pkg/util"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+type ClusterAutoscaling struct {
+	MinSize int `yaml:"minSize"`
+	MaxSize int `yaml:"maxSize"`
+}
+
+type ClusterNodePool struct {
+	Name        string              `yaml:"name,omitempty"`
+	NodeType    string              `yaml:"nodeType,omitempty"`
+	Quantity    int                 `yaml:"quantity,omitempty"`
+	Autoscaling *ClusterAutoscaling `yaml:"autoscaling,omitempty"`
+	Labels      []string            `yaml:"labels,omitempty"`
+	Taints      []string            `yaml:"taints,omitempty"`
+}
+
+// Cluster describes the cluster sym run creates when the target cluster does not exist yet
+type Cluster struct {
+	Region          string            `yaml:"region,omitempty"`
+	KubeVersion     string            `yaml:"kubeVersion,omitempty"`
+	HighlyAvailable bool              `yaml:"highlyAvailable,omitempty"`
+	NodePools       []ClusterNodePool `yaml:"nodePools,omitempty"`
+}
+
+// ClusterOverrides holds command line flags that take precedence over the cluster section
+type ClusterOverrides struct {
+	Region          string `json:"region,omitempty"`
+	KubeVersion     string `json:"kubeVersion,omitempty"`
+	HighlyAvailable *bool  `json:"highlyAvailable,omitempty"`
+	NodeType        string `json:"nodeType,omitempty"`
+	NodeCount       int    `json:"nodeCount,omitempty"`
+}
+
+func (c *Cluster) setDefaults() error {
+	if c.Region == "" {
+		c.Region = "germany-1"
+	}
+
+	if c.KubeVersion == "" {
+		c.KubeVersion = "latest"
+	}
+
+	if len(c.NodePools) == 0 {
+		c.NodePools = []ClusterNodePool{{
+			NodeType: "general-1",
+			Quantity: 2,
+			Autoscaling: &ClusterAutoscaling{
+				MinSize: 2,
+				MaxSize: 10,
+			},
+		}}
+	}
+
+	for i := range c.NodePools {
+		pool := &c.NodePools[i]
+
+		if pool.NodeType == "" {
+			pool.NodeType = "general-1"
+		}
+
+		if pool.Autoscaling != nil && pool.Quantity < pool.Autoscaling.MinSize {
+			pool.Quantity = pool.Autoscaling.MinSize
+		}
+
+		if pool.Quantity < 1 {
+			pool.Quantity = 1
+		}
+	}
+
+	return c.validate()
+}
+
+// validate checks the node pools against what the API accepts
+func (c *Cluster) validate() error {
+	for i, pool := range c.NodePools {
+		if pool.Quantity < 1 {
+			return fmt.Errorf("Invalid quantity %d for node pool %d, at least 1 node is required", pool.Quantity, i+1)
+		}
+
+		if pool.Autoscaling != nil {
+			if pool.Autoscaling.MinSize < 1 || pool.Autoscaling.MaxSize < pool.Autoscaling.MinSize {
+				return fmt.Errorf("Invalid autoscaling settings for node pool %d: minSize %d, maxSize %d", i+1, pool.Autoscaling.MinSize, pool.Autoscaling.MaxSize)
+			}
+
+			if pool.Quantity < pool.Autoscaling.MinSize || pool.Quantity > pool.Autoscaling.MaxSize {
+				return fmt.Errorf("Invalid quantity %d for node pool %d, it must be between the autoscaling minSize %d and maxSize %d", pool.Quantity, i+1, pool.Autoscaling.MinSize, pool.Autoscaling.MaxSize)
+			}
+		}
+
+		// fail early on labels and taints that the API would reject
+		_, _, err := util.ParseTaintsAndLabels(pool.Taints, pool.Labels)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Override applies the command line overrides and validates the result again
+func (c *Cluster) Override(overrides ClusterOverrides) error {
+	if overrides.NodeCount < 0 {
+		return fmt.Errorf("Invalid node count %d, at least 1 node is required", overrides.NodeCount)
+	}
+
+	if overrides.Region != "" {
+		c.Region = overrides.Region
+	}
+
+	if overrides.KubeVersion != "" {
+		c.KubeVersion = overrides.KubeVersion
+	}
+
+	if overrides.HighlyAvailable != nil {
+		c.HighlyAvailable = *overrides.HighlyAvailable
+	}
+
+	for i := range c.NodePools {
+		if overrides.NodeType != "" {
+			c.NodePools[i].NodeType = overrides.NodeType
+		}
+
+		if overrides.NodeCount > 0 {
+			c.NodePools[i].Quantity = overrides.NodeCount
+		}
+	}
+
+	return c.validate()
+}
+
+func (c *Cluster) ClusterInput(clusterName string) (*symbiosis.ClusterInput, error) {
+	nodePools := make([]symbiosis.ClusterNodePoolInput, len(c.NodePools))
+
+	for i, pool := range c.NodePools {
+		taints, labels, err := util.ParseTaintsAndLabels(pool.Taints, pool.Labels)
+
+		if err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, symbiosis.NodeLabel{
+			Key:   "managed-by",
+			Value: "sym-cli",
+		})
+
+		name := pool.Name
+
+		if name == "" {
+			name = fmt.Sprintf("%s-pool-%d", clusterName, i+1)
+		}
+
+		var autoscaling symbiosis.AutoscalingSettings
+
+		if pool.Autoscaling != nil {
+			autoscaling = symbiosis.AutoscalingSettings{
+				Enabled: true,
+				MinSize: pool.Autoscaling.MinSize,
+				MaxSize: pool.Autoscaling.MaxSize,
+			}
+		}
+
+		nodePools[i] = symbiosis.ClusterNodePoolInput{
+			Name:         name,
+			NodeTypeName: pool.NodeType,
+			Quantity:     pool.Quantity,
+			Autoscaling:  autoscaling,
+			Labels:       labels,
+			Taints:       taints,
+		}
+	}
+
+	return &symbiosis.ClusterInput{
+		Name:              clusterName,
+		Nodes:             nodePools,
+		IsHighlyAvailable: c.HighlyAvailable,
+		Region:            c.Region,
+		KubeVersion:       c.KubeVersion,
+	}, nil
+}
@@ -4,16 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"html/template"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
+	"text/template"
 
 	"github.com/manifoldco/promptui"
 	"github.com/symbiosis-cloud/cli/pkg/builder"
 	"github.com/symbiosis-cloud/cli/pkg/identity"
+	"github.com/symbiosis-cloud/cli/pkg/secrets"
 	"github.com/symbiosis-cloud/cli/pkg/symcommand"
 	"github.com/symbiosis-cloud/cli/pkg/testing"
 	"github.com/symbiosis-cloud/cli/pkg/util"
@@ -23,25 +24,32 @@ import (
 )
 
 type Deployment struct {
-	Helm      []builder.HelmDeployment `yaml:"helm,omitempty"`
-	Kustomize []struct {
-		Path string `yaml:"path"`
-	} `yaml:"kustomize,omitempty"`
+	Helm      []builder.HelmDeployment      `yaml:"helm,omitempty"`
+	Kustomize []builder.KustomizeDeployment `yaml:"kustomize,omitempty"`
 }
 
 type Test struct {
-	Image   string `yaml:"image,omitempty"`
-	Command string `yaml:"command,omitempty"`
+	Image     string `yaml:"image,omitempty"`
+	Command   string `yaml:"command,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
 }
 
 type ProjectConfig struct {
 	Project *symbiosis.Project
 	Deploy  *Deployment `yaml:"deploy"`
 	Test    []Test      `yaml:"test,omitempty"`
-	Preview struct {
-	} `yaml:"preview"`
+	Preview *Preview    `yaml:"preview,omitempty"`
+	Cluster *Cluster    `yaml:"cluster,omitempty"`
+	Hooks   *Hooks      `yaml:"hooks,omitempty"`
+	Jobs    []Job       `yaml:"jobs,omitempty"`
+
+	Namespaces map[string]*Namespace `yaml:"namespaces,omitempty"`
+
+	Environments map[string]*Environment `yaml:"environments,omitempty"`
+	Environment  *Environment            `yaml:"-"`
 
 	builders        []builder.Builder
+	secrets         symbiosis.SecretCollection
 	TestRunner      *testing.TestRunner
 	Path            string
 	rawConfig       []byte
@@ -49,7 +57,9 @@ type ProjectConfig struct {
 	commandOpts     *symcommand.CommandOpts
 	ProjectFilePath string
 	identity        *identity.ClusterIdentity
-	Clientset       *kubernetes.Clientset
+	Clientset       kubernetes.Interface
+	hookRecorder    HookRecorder
+	failureHooksRun bool
 }
 
 func (p *ProjectConfig) Parse() error {
@@ -63,44 +73,88 @@ func (p *ProjectConfig) Parse() error {
 		return err
 	}
 
-	secrets, err := p.client.Secret.GetSecretsByProject(p.Project.Name)
+	parsedFile, err := p.render(f, false)
 
 	if err != nil {
 		return err
 	}
 
-	parsedFile := bytes.NewBuffer([]byte{})
+	// unknown keys are rejected like sym validate does instead of being silently ignored
+	err = yaml.UnmarshalStrict(parsedFile, &p)
 
-	t := template.Must(template.New("parse-project-config").Funcs(template.FuncMap{
-		"Secret": func(secretName string) (string, error) {
-			if secret := secrets[secretName]; secret != nil {
-				return secret.Value, nil
-			}
-			return "", fmt.Errorf("Secret %s could not be found in project %s", secretName, p.Project.Name)
-		},
-	}).Parse(string(f)))
+	if err != nil {
+		return fmt.Errorf("Could not parse %s, run sym validate for details: %v", p.Path, err)
+	}
+
+	if p.Deploy == nil {
+		return fmt.Errorf("No deployments configured... cannot continue")
+	}
+
+	if environment := p.commandOpts.Environment; environment != "" {
+		config, ok := p.Environments[environment]
+
+		if !ok {
+			return fmt.Errorf("Environment %s not found in %s", environment, p.Path)
+		}
+
+		// an environment without settings is declared as an empty key
+		if config == nil {
+			config = &Environment{}
+		}
+
+		p.Environment = config
+		p.applyEnvironment(p.Environment)
+	}
+
+	if p.Cluster == nil {
+		p.Cluster = &Cluster{}
+	}
+
+	err = p.Cluster.setDefaults()
 
-	err = t.Execute(parsedFile, nil)
 	if err != nil {
 		return err
 	}
 
-	err = yaml.Unmarshal(parsedFile.Bytes(), &p)
+	if p.Hooks == nil {
+		p.Hooks = &Hooks{}
+	}
+
+	err = p.validateJobs()
 
 	if err != nil {
 		return err
 	}
 
-	if p.Deploy == nil {
-		return fmt.Errorf("No deployments configured... cannot continue")
+	if p.Preview != nil {
+		err = p.Preview.setDefaults()
+
+		if err != nil {
+			return err
+		}
 	}
 
 	if p.Deploy.Helm != nil {
-		helm := builder.NewHelmBuilder(p.Deploy.Helm, filepath.Dir(p.Path), p.commandOpts)
+		helm, err := builder.NewHelmBuilder(p.Deploy.Helm, filepath.Dir(p.Path), p.Project.Name, p.commandOpts, p.secret)
+
+		if err != nil {
+			return err
+		}
+
+		helm.SetReleaseHooks(
+			func(release string) error { return p.runJobs(release, "") },
+			func(release string) error { return p.runJobs("", release) },
+		)
 
 		p.builders = append(p.builders, helm)
 	}
 
+	if p.Deploy.Kustomize != nil {
+		kustomize := builder.NewKustomizeBuilder(p.Deploy.Kustomize, filepath.Dir(p.Path), p.Project.Name, p.commandOpts)
+
+		p.builders = append(p.builders, kustomize)
+	}
+
 	if p.Test != nil {
 		if len(p.Test) == 0 {
 			return fmt.Errorf("No tests given")
@@ -108,7 +162,7 @@ func (p *ProjectConfig) Parse() error {
 
 		jobs := make([]*testing.TestJob, len(p.Test))
 		for i, test := range p.Test {
-			jobs[i] = testing.NewTestJob(test.Image, strings.Split(test.Command, " "))
+			jobs[i] = testing.NewTestJob(test.Image, strings.Split(test.Command, " "), test.Namespace)
 		}
 
 		p.TestRunner, err = testing.NewTestRunner(jobs, p.Clientset, p.commandOpts)
@@ -129,16 +183,144 @@ func (p *ProjectConfig) Parse() error {
 	return nil
 }
 
+// Render returns sym.yaml with all template functions resolved. Secret values are replaced
+// with REDACTED when redact is set.
+func (p *ProjectConfig) Render(redact bool) ([]byte, error) {
+	f, err := os.ReadFile(p.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.render(f, redact)
+}
+
+func (p *ProjectConfig) render(content []byte, redact bool) ([]byte, error) {
+	secrets, err := p.getSecrets(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.secrets = secrets
+
+	return renderConfig(content, templateFuncs(filepath.Dir(p.Path), func(secretName string) (string, error) {
+		value, err := p.secret(secretName)
+
+		if err != nil {
+			return "", err
+		}
+
+		if redact {
+			return REDACTED, nil
+		}
+
+		return value, nil
+	}))
+}
+
+// secret returns the value of a secret fetched while rendering sym.yaml
+func (p *ProjectConfig) secret(name string) (string, error) {
+	secret := p.secrets[name]
+
+	if secret == nil {
+		return "", fmt.Errorf("Secret %s could not be found in project %s", name, p.Project.Name)
+	}
+
+	return secret.Value, nil
+}
+
+// getSecrets fetches the secrets of the selected environment or all project secrets when none is selected
+func (p *ProjectConfig) getSecrets(content []byte) (symbiosis.SecretCollection, error) {
+	name := p.commandOpts.Environment
+
+	if name == "" {
+		return p.client.Secret.GetSecretsByProject(p.Project.Name)
+	}
+
+	environment, err := loadEnvironment(content, p.Path, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	secretEnvironment, err := environment.SecretsEnvironment(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.commandOpts.Logger.Info().Msgf("Using %s secrets for environment %s", secretEnvironment, name)
+
+	return secrets.NewSecretManager(p.client, p.Project.Name, secretEnvironment).GetSecrets()
+}
+
+func (p *ProjectConfig) applyEnvironment(environment *Environment) {
+	for i := range p.Deploy.Helm {
+		deployment := &p.Deploy.Helm[i]
+		values, ok := environment.Values[deployment.Name]
+
+		if !ok {
+			continue
+		}
+
+		deployment.Values = builder.MergeValues(
+			builder.NormalizeValues(deployment.Values).(map[string]interface{}),
+			builder.NormalizeValues(values).(map[string]interface{}),
+		)
+	}
+}
+
+func renderConfig(content []byte, funcs template.FuncMap) ([]byte, error) {
+	parsedFile := bytes.NewBuffer([]byte{})
+
+	t, err := template.New("parse-project-config").Funcs(funcs).Parse(string(content))
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.Execute(parsedFile, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parsedFile.Bytes(), nil
+}
+
 func (p *ProjectConfig) RunTests(testOutputDir string) error {
 	if p.TestRunner == nil {
 		p.commandOpts.Logger.Info().Msg("No tests to run")
 		return nil
 	}
 
+	err := p.ensureNamespaces(p.TestRunner.Namespaces())
+
+	if err != nil {
+		return err
+	}
+
 	return p.TestRunner.Run(testOutputDir)
 }
 
 func (p *ProjectConfig) RunBuilders() error {
+	err := p.runBuilders()
+
+	if err != nil {
+		p.RunFailureHooks(err)
+	}
+
+	return err
+}
+
+func (p *ProjectConfig) runBuilders() error {
+	err := p.runHooks(HOOK_PRE_BUILD, p.Hooks.PreBuild)
+
+	if err != nil {
+		return err
+	}
+
 	for _, builder := range p.builders {
 		err := builder.Build()
 
@@ -150,16 +332,155 @@ func (p *ProjectConfig) RunBuilders() error {
 	return nil
 }
 
+// Requirements returns the requirements of all configured builders
+func (p *ProjectConfig) Requirements() ([]builder.Requirement, error) {
+	var requirements []builder.Requirement
+
+	for _, b := range p.builders {
+		r, err := builder.Requirements(b)
+
+		if err != nil {
+			return nil, err
+		}
+
+		requirements = append(requirements, r...)
+	}
+
+	return requirements, nil
+}
+
 func (p *ProjectConfig) RunDeploy() error {
-	for _, builder := range p.builders {
-		err := builder.Deploy()
+	err := p.runDeploy()
+
+	if err != nil {
+		p.RunFailureHooks(err)
+		return err
+	}
+
+	// the deploy itself succeeded, a missing history entry only affects sym rollback
+	record, err := p.recordApply(HISTORY_ACTION_APPLY)
+
+	if err != nil {
+		p.commandOpts.Logger.Warn().Msgf("Could not record apply in the deployment history: %v", err)
+	} else if record != nil {
+		p.commandOpts.Logger.Info().Msgf("Recorded apply %s", record.ID)
+	}
+
+	return nil
+}
+
+func (p *ProjectConfig) runDeploy() (err error) {
+	var deployed []*builder.HelmBuilder
+
+	// a failure after the Helm releases were installed, such as a kustomization or the postDeploy hook,
+	// reverts them as well
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		for i := len(deployed) - 1; i >= 0; i-- {
+			deployed[i].Rollback()
+		}
+	}()
+
+	err = p.runHooks(HOOK_PRE_DEPLOY, p.Hooks.PreDeploy)
+
+	if err != nil {
+		return err
+	}
+
+	// also creates the client up front, jobs of releases in the same wave run concurrently
+	err = p.ensureNamespaces(p.DeploymentNamespaces())
+
+	if err != nil {
+		return err
+	}
+
+	if len(p.Jobs) > 0 {
+		// jobs without before or after run before any deployment
+		err = p.runJobs("", "")
 
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	for _, b := range p.builders {
+		err := b.Deploy()
+
+		if err != nil {
+			return err
+		}
+
+		if helm, ok := b.(*builder.HelmBuilder); ok {
+			deployed = append(deployed, helm)
+		}
+	}
+
+	return p.runHooks(HOOK_POST_DEPLOY, p.Hooks.PostDeploy)
+}
+
+// Close removes the temporary files of the builders, such as the isolated Helm home. Every command that
+// loads a project defers it.
+// reload parses sym.yaml again into a new project with the same cluster, the caller closes the returned project
+func (p *ProjectConfig) reload() (*ProjectConfig, error) {
+	reloaded := &ProjectConfig{
+		Path:            p.Path,
+		ProjectFilePath: p.ProjectFilePath,
+		Project:         p.Project,
+		Clientset:       p.Clientset,
+		client:          p.client,
+		commandOpts:     p.commandOpts,
+		identity:        p.identity,
+		hookRecorder:    p.hookRecorder,
+	}
+
+	err := reloaded.Parse()
+
+	if err != nil {
+		reloaded.Close()
+		return nil, err
+	}
+
+	return reloaded, nil
+}
+
+func (p *ProjectConfig) Close() {
+	for _, b := range p.builders {
+		if helm, ok := b.(*builder.HelmBuilder); ok {
+			helm.Close()
+		}
+	}
+}
+
+// Lock resolves all remote Helm charts to an exact version and writes them to sym.lock next to sym.yaml
+func (p *ProjectConfig) Lock() (*builder.Lockfile, error) {
+	lockfile := &builder.Lockfile{Charts: map[string]*builder.LockedChart{}}
+
+	for _, b := range p.builders {
+		helm, ok := b.(*builder.HelmBuilder)
+
+		if !ok {
+			continue
+		}
+
+		locked, err := helm.Lock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		lockfile = locked
+	}
+
+	err := lockfile.Write(builder.LockfilePath(filepath.Dir(p.Path)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lockfile, nil
 }
 
 func (p *ProjectConfig) PromptProject(path string) (*symbiosis.Project, error) {
@@ -215,6 +536,10 @@ func (p *ProjectConfig) PromptProject(path string) (*symbiosis.Project, error) {
 }
 
 func (p *ProjectConfig) SetIdentity(identity *identity.ClusterIdentity) {
+	if p.identity != identity {
+		p.Clientset = nil
+	}
+
 	p.identity = identity
 
 	if len(p.builders) > 0 {
@@ -233,7 +558,7 @@ func NewProjectConfig(file string, opts *symcommand.CommandOpts, client *symbios
 		return nil, err
 	}
 
-	var clientset *kubernetes.Clientset
+	var clientset kubernetes.Interface
 
 	if identity != nil {
 
@@ -0,0 +1,96 @@
+package project
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+func TestParseEnvironment(t *testing.T) {
+	config := `deploy:
+  helm:
+    - name: api
+      chart: oci://ghcr.io/example/api
+      values:
+        replicas: 1
+environments:
+  development:
+  production:
+    namespace: live
+    values:
+      api:
+        replicas: 3
+`
+
+	tests := []struct {
+		name        string
+		environment string
+		namespace   string
+		replicas    interface{}
+		err         string
+	}{
+		{name: "no environment", replicas: 1},
+		{name: "empty environment", environment: "development", replicas: 1},
+		{name: "environment values", environment: "production", namespace: "live", replicas: 3},
+		{name: "unknown environment", environment: "staging", err: "Environment staging not found in"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := symbiosis.NewClientFromAPIKey("test", symbiosis.WithEndpoint(server.URL))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "sym.yaml")
+
+	err = os.WriteFile(file, []byte(config), 0600)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &ProjectConfig{
+				Path:        file,
+				Project:     &symbiosis.Project{Name: "web"},
+				client:      client,
+				commandOpts: &symcommand.CommandOpts{Logger: zerolog.Nop(), Environment: test.environment},
+			}
+
+			err := p.Parse()
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if test.environment != "" && (p.Environment == nil || p.Environment.Namespace != test.namespace) {
+				t.Errorf("expected environment with namespace %q, got %#v", test.namespace, p.Environment)
+			}
+
+			if replicas := p.Deploy.Helm[0].Values["replicas"]; replicas != test.replicas {
+				t.Errorf("expected %v replicas, got %v", test.replicas, replicas)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Destroy removes everything deployed from sym.yaml in the reverse order of the deploy. Namespaces are only
+// deleted with deleteNamespaces and only when sym created them for the same project and environment and no
+// other releases installed by sym are left in them, namespaces of previews are left to sym preview down.
+func (p *ProjectConfig) Destroy(deleteNamespaces bool) ([]*builder.DestroyResult, error) {
+	var results []*builder.DestroyResult
+
+	for i := len(p.builders) - 1; i >= 0; i-- {
+		removed, err := p.builders[i].Destroy()
+
+		results = append(results, removed...)
+
+		if err != nil {
+			return results, err
+		}
+	}
+
+	removed, err := p.destroyJobs()
+
+	results = append(results, removed...)
+
+	if err != nil {
+		return results, err
+	}
+
+	if !deleteNamespaces {
+		return results, nil
+	}
+
+	removed, err = p.destroyNamespaces()
+
+	return append(results, removed...), err
+}
+
+// destroyJobs deletes the jobs kept because of their retention
+func (p *ProjectConfig) destroyJobs() ([]*builder.DestroyResult, error) {
+	if len(p.Jobs) == 0 {
+		return nil, nil
+	}
+
+	clientset, err := p.clientset()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*builder.DestroyResult
+
+	propagation := metav1.DeletePropagationBackground
+
+	for _, job := range p.Jobs {
+		namespace := p.jobNamespace(&job)
+		jobsApi := clientset.BatchV1().Jobs(namespace)
+
+		jobs, err := jobsApi.List(context.TODO(), metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s,%s=%s", MANAGED_BY_LABEL, MANAGED_BY, JOB_LABEL, job.Name),
+		})
+
+		if err != nil {
+			return results, err
+		}
+
+		for _, item := range jobs.Items {
+			err = jobsApi.Delete(context.TODO(), item.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+
+			if err != nil && !errors.IsNotFound(err) {
+				return results, err
+			}
+
+			results = append(results, &builder.DestroyResult{
+				Kind:      "job",
+				Name:      item.Name,
+				Namespace: namespace,
+				Status:    builder.DESTROY_STATUS_REMOVED,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+func (p *ProjectConfig) destroyNamespaces() ([]*builder.DestroyResult, error) {
+	clientset, err := p.clientset()
+
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := p.DeploymentNamespaces()
+
+	for name := range p.Namespaces {
+		namespaces = append(namespaces, name)
+	}
+
+	helm, err := p.pruneBuilder()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*builder.DestroyResult
+
+	namespacesApi := clientset.CoreV1().Namespaces()
+
+	for _, name := range uniqueNamespaces(namespaces) {
+		namespace, err := namespacesApi.Get(context.TODO(), name, metav1.GetOptions{})
+
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return results, err
+		}
+
+		if namespace.Labels[MANAGED_BY_LABEL] != MANAGED_BY || namespace.Labels[PREVIEW_LABEL] != "" {
+			p.commandOpts.Logger.Info().Msgf("Keeping namespace %s, it was not created by sym", name)
+			continue
+		}
+
+		if !p.ownsNamespace(namespace) {
+			p.commandOpts.Logger.Info().Msgf("Keeping namespace %s, it was created for another project or environment", name)
+			continue
+		}
+
+		releases, err := helm.NamespaceReleases(name)
+
+		if err != nil {
+			return results, err
+		}
+
+		if len(releases) > 0 {
+			p.commandOpts.Logger.Info().Msgf("Keeping namespace %s, it still holds the release %s", name, releases[0].Name)
+			continue
+		}
+
+		p.commandOpts.Logger.Info().Msgf("Deleting namespace %s", name)
+
+		err = namespacesApi.Delete(context.TODO(), name, metav1.DeleteOptions{})
+
+		if err != nil && !errors.IsNotFound(err) {
+			return results, err
+		}
+
+		results = append(results, &builder.DestroyResult{
+			Kind:   "namespace",
+			Name:   name,
+			Status: builder.DESTROY_STATUS_REMOVED,
+		})
+	}
+
+	return results, nil
+}
+
+// ownsNamespace reports whether a namespace was created by a deploy of the same project and environment
+func (p *ProjectConfig) ownsNamespace(namespace *v1.Namespace) bool {
+	if namespace.Labels[builder.PROJECT_LABEL] != builder.LabelValue(p.Project.Name) {
+		return false
+	}
+
+	return namespace.Labels[builder.ENVIRONMENT_LABEL] == builder.LabelValue(p.commandOpts.Environment)
+}
+
+// ReleaseNames returns the names of all Helm deployments
+func (p *ProjectConfig) ReleaseNames() []string {
+	var names []string
+
+	if p.Deploy != nil {
+		for _, d := range p.Deploy.Helm {
+			names = append(names, d.Name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,225 @@
+package project
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/manifest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	DRIFT_STATUS_DRIFTED = "drifted"
+	DRIFT_STATUS_MISSING = "missing"
+)
+
+// DriftedObject is a live object that no longer matches what sym.yaml renders, or is missing altogether
+type DriftedObject struct {
+	Builder   string                `json:"builder"`
+	Source    string                `json:"source"`
+	Kind      string                `json:"kind"`
+	Name      string                `json:"name"`
+	Namespace string                `json:"namespace"`
+	Status    string                `json:"status"`
+	Fields    []*manifest.FieldDiff `json:"fields,omitempty"`
+}
+
+// Key identifies the object within the cluster
+func (d *DriftedObject) Key() string {
+	return d.Builder + "/" + d.Source + "/" + d.Kind + "/" + d.Namespace + "/" + d.Name
+}
+
+type desiredObject struct {
+	manifest *builder.Manifest
+	object   *unstructured.Unstructured
+}
+
+// DriftCheck compares live objects with the desired state of sym.yaml. The desired state is rendered again
+// whenever sym.yaml or a release revision changes, so a long running check follows new deploys.
+type DriftCheck struct {
+	original    *ProjectConfig
+	project     *ProjectConfig
+	client      *manifest.Client
+	desired     []*desiredObject
+	fingerprint string
+	rendered    bool
+}
+
+// NewDriftCheck renders every builder without deploying anything
+func (p *ProjectConfig) NewDriftCheck() (*DriftCheck, error) {
+	check := &DriftCheck{original: p, project: p}
+
+	err := check.refresh()
+
+	if err != nil {
+		return nil, err
+	}
+
+	check.rendered = true
+
+	return check, nil
+}
+
+// Close cleans up the project parsed again by the check, the original project is closed by its owner
+func (c *DriftCheck) Close() {
+	if c.project != c.original {
+		c.project.Close()
+	}
+}
+
+// refresh renders the desired state again when sym.yaml or a release changed since the last render. Kinds
+// discovered from the cluster are always forgotten, so custom resources installed since are compared too.
+func (c *DriftCheck) refresh() error {
+	fingerprint, err := c.project.driftFingerprint()
+
+	if err != nil {
+		return err
+	}
+
+	if fingerprint == c.fingerprint {
+		c.client.Reset()
+		return nil
+	}
+
+	project := c.project
+
+	if c.fingerprint != "" {
+		c.project.commandOpts.Logger.Info().Msg("sym.yaml or a release changed, rendering the desired state again")
+
+		project, err = c.project.reload()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	desired, client, err := project.desiredObjects()
+
+	if err != nil {
+		if project != c.project {
+			project.Close()
+		}
+
+		return err
+	}
+
+	if project != c.project {
+		c.Close()
+		c.project = project
+	}
+
+	c.client = client
+	c.desired = desired
+	c.fingerprint = fingerprint
+
+	return nil
+}
+
+// desiredObjects renders every builder and returns the objects it would deploy
+func (p *ProjectConfig) desiredObjects() ([]*desiredObject, *manifest.Client, error) {
+	manifests, client, err := p.renderManifests()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var desired []*desiredObject
+
+	for _, m := range manifests {
+		objects, err := manifest.Parse(m.Content)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, object := range objects {
+			desired = append(desired, &desiredObject{m, object})
+		}
+	}
+
+	return desired, client, nil
+}
+
+// driftFingerprint hashes sym.yaml and the current revision of every release, a change to either means the
+// desired state has to be rendered again
+func (p *ProjectConfig) driftFingerprint() (string, error) {
+	content, err := os.ReadFile(p.Path)
+
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(content)
+
+	if helm := p.helmBuilder(); helm != nil {
+		revisions, err := helm.Revisions()
+
+		if err != nil {
+			return "", err
+		}
+
+		names := make([]string, 0, len(revisions))
+
+		for name := range revisions {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(hash, "\n%s/%s:%d", revisions[name].Namespace, name, revisions[name].Revision)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Run returns every object whose live state differs from the desired state. Fields the API server or
+// controllers populate are ignored. The desired state is refreshed first unless it was just rendered.
+func (c *DriftCheck) Run(ctx context.Context) ([]*DriftedObject, error) {
+	if !c.rendered {
+		err := c.refresh()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.rendered = false
+
+	var drifted []*DriftedObject
+
+	for _, d := range c.desired {
+		live, err := c.client.Get(ctx, d.object, d.manifest.Namespace)
+
+		if err != nil {
+			return nil, err
+		}
+
+		object := &DriftedObject{
+			Builder:   d.manifest.Builder,
+			Source:    d.manifest.Name,
+			Kind:      d.object.GetKind(),
+			Name:      d.object.GetName(),
+			Namespace: d.object.GetNamespace(),
+		}
+
+		if live == nil {
+			object.Status = DRIFT_STATUS_MISSING
+		} else if object.Fields = manifest.Diff(d.object, live); len(object.Fields) > 0 {
+			object.Status = DRIFT_STATUS_DRIFTED
+		} else {
+			continue
+		}
+
+		drifted = append(drifted, object)
+	}
+
+	return drifted, nil
+}
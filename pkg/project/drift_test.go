@@ -0,0 +1,48 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDriftFingerprint(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sym.yaml")
+
+	write := func(content string) {
+		if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fingerprint := func(p *ProjectConfig) string {
+		value, err := p.driftFingerprint()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return value
+	}
+
+	p := &ProjectConfig{Path: file}
+
+	write("deploy: {}\n")
+	first := fingerprint(p)
+
+	if second := fingerprint(p); second != first {
+		t.Errorf("expected an unchanged file to keep fingerprint %s, got %s", first, second)
+	}
+
+	write("deploy:\n  kustomize: []\n")
+
+	if changed := fingerprint(p); changed == first {
+		t.Errorf("expected a changed file to change the fingerprint")
+	}
+
+	_, err := (&ProjectConfig{Path: filepath.Join(t.TempDir(), "missing.yaml")}).driftFingerprint()
+
+	if err == nil {
+		t.Errorf("expected an error for a missing sym.yaml")
+	}
+}
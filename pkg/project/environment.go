@@ -0,0 +1,94 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/symbiosis-cloud/cli/pkg/secrets"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+	"gopkg.in/yaml.v2"
+)
+
+type Environment struct {
+	Namespace string `yaml:"namespace,omitempty"`
+	Cluster   string `yaml:"cluster,omitempty"`
+	// Secrets selects the project secrets to use, defaults to the environment name
+	Secrets string `yaml:"secrets,omitempty"`
+	// Values overrides Helm values per deployment name
+	Values map[string]map[string]interface{} `yaml:"values,omitempty"`
+}
+
+func (e *Environment) SecretsEnvironment(name string) (symbiosis.ProjectEnvironment, error) {
+	secretEnvironment := e.Secrets
+
+	if secretEnvironment == "" {
+		secretEnvironment = name
+	}
+
+	environment, err := secrets.ParseEnvironment(secretEnvironment)
+
+	if err != nil {
+		return "", fmt.Errorf("Environment %s has no valid secrets environment, please set secrets to one of: %v", name, secrets.Environments)
+	}
+
+	return environment, nil
+}
+
+// loadEnvironment reads a single environment from sym.yaml before secrets are available by rendering it offline
+func loadEnvironment(content []byte, file string, name string) (*Environment, error) {
+	rendered, err := renderConfig(content, offlineFuncs(filepath.Dir(file)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		Environments map[string]*Environment `yaml:"environments"`
+	}
+
+	err = yaml.Unmarshal(rendered, &config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	environment, ok := config.Environments[name]
+
+	if !ok {
+		return nil, fmt.Errorf("Environment %s not found in %s", name, file)
+	}
+
+	if environment == nil {
+		environment = &Environment{}
+	}
+
+	return environment, nil
+}
+
+// ApplyEnvironment loads the environment selected with --env and uses its namespace unless --namespace has been set.
+// It returns nil when no environment has been selected.
+func ApplyEnvironment(flags *symcommand.DeploymentFlags) (*Environment, error) {
+	if flags.Environment == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(flags.File)
+
+	if err != nil {
+		return nil, err
+	}
+
+	environment, err := loadEnvironment(content, flags.File, flags.Environment)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !flags.NamespaceSet && environment.Namespace != "" {
+		flags.Namespace = environment.Namespace
+	}
+
+	return environment, nil
+}
@@ -0,0 +1,342 @@
+package project
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	HISTORY_LABEL  = "symbiosis.host/history"
+	HISTORY_PREFIX = "sym-apply-"
+	HISTORY_LIMIT  = 25
+
+	HISTORY_ACTION_APPLY    = "apply"
+	HISTORY_ACTION_ROLLBACK = "rollback"
+)
+
+// ApplyRecord captures the revision of every release after an apply or rollback, so the whole project can be
+// restored to that point with sym rollback. Records are stored as ConfigMaps in the target namespace.
+type ApplyRecord struct {
+	ID          string                              `json:"id"`
+	Time        time.Time                           `json:"time"`
+	Action      string                              `json:"action"`
+	GitSHA      string                              `json:"gitSha,omitempty"`
+	FileHash    string                              `json:"fileHash"`
+	User        string                              `json:"user"`
+	Environment string                              `json:"environment,omitempty"`
+	Releases    map[string]*builder.ReleaseRevision `json:"releases"`
+}
+
+// ReleaseList returns the releases of a record as name:revision pairs
+func (r *ApplyRecord) ReleaseList() string {
+	names := make([]string, 0, len(r.Releases))
+
+	for name := range r.Releases {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s:%d", name, r.Releases[name].Revision)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// recordApply stores the current release revisions as a new history entry and removes the oldest entries
+func (p *ProjectConfig) recordApply(action string) (*ApplyRecord, error) {
+	helm := p.helmBuilder()
+
+	if helm == nil {
+		return nil, nil
+	}
+
+	revisions, err := helm.Revisions()
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(p.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(content)
+
+	// sym.yaml does not have to be in a git repository
+	gitSHA, _ := util.GitSHA(filepath.Dir(p.Path))
+
+	now := time.Now().UTC()
+
+	record := &ApplyRecord{
+		ID:          fmt.Sprintf("%s-%s", now.Format("20060102-150405"), util.RandomString(4)),
+		Time:        now,
+		Action:      action,
+		GitSHA:      gitSHA,
+		FileHash:    hex.EncodeToString(sum[:]),
+		User:        currentUser(),
+		Environment: p.commandOpts.Environment,
+		Releases:    revisions,
+	}
+
+	releases, err := json.Marshal(record.Releases)
+
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := p.clientset()
+
+	if err != nil {
+		return nil, err
+	}
+
+	configMaps := clientset.CoreV1().ConfigMaps(p.commandOpts.Namespace)
+
+	_, err = configMaps.Create(context.TODO(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: HISTORY_PREFIX + record.ID,
+			Labels: map[string]string{
+				MANAGED_BY_LABEL: MANAGED_BY,
+				HISTORY_LABEL:    p.historyLabel(),
+			},
+		},
+		Data: map[string]string{
+			"id":          record.ID,
+			"time":        record.Time.Format(time.RFC3339Nano),
+			"action":      record.Action,
+			"gitSha":      record.GitSHA,
+			"fileHash":    record.FileHash,
+			"user":        record.User,
+			"environment": record.Environment,
+			"releases":    string(releases),
+		},
+	}, metav1.CreateOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := p.History()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := HISTORY_LIMIT; i < len(history); i++ {
+		err = configMaps.Delete(context.TODO(), HISTORY_PREFIX+history[i].ID, metav1.DeleteOptions{})
+
+		if err != nil {
+			p.commandOpts.Logger.Warn().Msgf("Could not remove history entry %s: %v", history[i].ID, err)
+		}
+	}
+
+	return record, nil
+}
+
+// History returns the recorded applies of the project in the target namespace, newest first
+func (p *ProjectConfig) History() ([]*ApplyRecord, error) {
+	clientset, err := p.clientset()
+
+	if err != nil {
+		return nil, err
+	}
+
+	configMaps, err := clientset.CoreV1().ConfigMaps(p.commandOpts.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", HISTORY_LABEL, p.historyLabel()),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var history []*ApplyRecord
+
+	for _, configMap := range configMaps.Items {
+		record, err := parseApplyRecord(configMap.Data)
+
+		if err != nil {
+			p.commandOpts.Logger.Warn().Msgf("Skipping history entry %s: %v", configMap.Name, err)
+			continue
+		}
+
+		// environments may share a namespace, each keeps its own history
+		if record.Environment != p.commandOpts.Environment {
+			continue
+		}
+
+		history = append(history, record)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Time.After(history[j].Time)
+	})
+
+	return history, nil
+}
+
+// Rollback restores every release to the revision captured by an apply. Without an id the apply before the
+// latest one is used, undoing the last apply. Earlier rollbacks are not counted as applies.
+func (p *ProjectConfig) Rollback(id string) (*ApplyRecord, error) {
+	helm := p.helmBuilder()
+
+	if helm == nil {
+		return nil, fmt.Errorf("No Helm deployments configured")
+	}
+
+	history, err := p.History()
+
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := rollbackTarget(history, id)
+
+	if err != nil {
+		return nil, fmt.Errorf("%v in namespace %s", err, p.commandOpts.Namespace)
+	}
+
+	p.commandOpts.Logger.Info().Msgf("Rolling back to apply %s from %s", target.ID, target.Time.Format(time.RFC3339))
+
+	names := make([]string, 0, len(target.Releases))
+
+	for name := range target.Releases {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var failed []string
+
+	for _, name := range names {
+		revision := target.Releases[name]
+
+		current, err := helm.RollbackRelease(name, revision)
+
+		if err != nil {
+			p.commandOpts.Logger.Error().Msgf("Could not roll back %s: %v", name, err)
+			failed = append(failed, name)
+		} else if current == revision.Revision {
+			p.commandOpts.Logger.Info().Msgf("%s is already at revision %d", name, revision.Revision)
+		} else {
+			p.commandOpts.Logger.Info().Msgf("%s rolled back from revision %d to %d", name, current, revision.Revision)
+		}
+	}
+
+	current, err := helm.Revisions()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range current {
+		if _, ok := target.Releases[name]; !ok {
+			p.commandOpts.Logger.Warn().Msgf("%s was not part of apply %s and is left unchanged", name, target.ID)
+		}
+	}
+
+	if len(failed) > 0 {
+		return target, fmt.Errorf("Rollback failed for %s", strings.Join(failed, ", "))
+	}
+
+	_, err = p.recordApply(HISTORY_ACTION_ROLLBACK)
+
+	if err != nil {
+		p.commandOpts.Logger.Warn().Msgf("Could not record rollback: %v", err)
+	}
+
+	return target, nil
+}
+
+func (p *ProjectConfig) helmBuilder() *builder.HelmBuilder {
+	for _, b := range p.builders {
+		if helm, ok := b.(*builder.HelmBuilder); ok {
+			return helm
+		}
+	}
+
+	return nil
+}
+
+// rollbackTarget returns the record with the given id, or without an id the apply before the latest apply
+func rollbackTarget(history []*ApplyRecord, id string) (*ApplyRecord, error) {
+	if id != "" {
+		for _, record := range history {
+			if record.ID == id {
+				return record, nil
+			}
+		}
+
+		return nil, fmt.Errorf("Apply %s not found", id)
+	}
+
+	var applies []*ApplyRecord
+
+	for _, record := range history {
+		if record.Action != HISTORY_ACTION_ROLLBACK {
+			applies = append(applies, record)
+		}
+	}
+
+	if len(applies) < 2 {
+		return nil, fmt.Errorf("No earlier apply to roll back to")
+	}
+
+	return applies[1], nil
+}
+
+// historyLabel returns the project name as a valid label value
+func (p *ProjectConfig) historyLabel() string {
+	return builder.LabelValue(p.Project.Name)
+}
+
+func parseApplyRecord(data map[string]string) (*ApplyRecord, error) {
+	recordTime, err := time.Parse(time.RFC3339Nano, data["time"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	record := &ApplyRecord{
+		ID:          data["id"],
+		Time:        recordTime,
+		Action:      data["action"],
+		GitSHA:      data["gitSha"],
+		FileHash:    data["fileHash"],
+		User:        data["user"],
+		Environment: data["environment"],
+	}
+
+	err = json.Unmarshal([]byte(data["releases"]), &record.Releases)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
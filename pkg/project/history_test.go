@@ -0,0 +1,231 @@
+package project
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseApplyRecord(t *testing.T) {
+	recordTime := time.Date(2022, 11, 3, 14, 5, 6, 789, time.UTC)
+
+	tests := []struct {
+		name     string
+		data     map[string]string
+		expected *ApplyRecord
+		err      bool
+	}{
+		{
+			name: "apply",
+			data: map[string]string{
+				"id":          "20221103140506-ab12",
+				"time":        recordTime.Format(time.RFC3339Nano),
+				"action":      HISTORY_ACTION_APPLY,
+				"gitSha":      "0123abc",
+				"fileHash":    "sha256:ff",
+				"user":        "ci",
+				"environment": "production",
+				"releases":    `{"api":{"namespace":"default","revision":3},"db":{"namespace":"data","revision":1}}`,
+			},
+			expected: &ApplyRecord{
+				ID:          "20221103140506-ab12",
+				Time:        recordTime,
+				Action:      HISTORY_ACTION_APPLY,
+				GitSHA:      "0123abc",
+				FileHash:    "sha256:ff",
+				User:        "ci",
+				Environment: "production",
+				Releases: map[string]*builder.ReleaseRevision{
+					"api": {Namespace: "default", Revision: 3},
+					"db":  {Namespace: "data", Revision: 1},
+				},
+			},
+		},
+		{
+			name: "optional fields missing",
+			data: map[string]string{
+				"id":       "20221103140506-cd34",
+				"time":     recordTime.Format(time.RFC3339Nano),
+				"action":   HISTORY_ACTION_APPLY,
+				"fileHash": "sha256:ff",
+				"releases": `{}`,
+			},
+			expected: &ApplyRecord{
+				ID:       "20221103140506-cd34",
+				Time:     recordTime,
+				Action:   HISTORY_ACTION_APPLY,
+				FileHash: "sha256:ff",
+				Releases: map[string]*builder.ReleaseRevision{},
+			},
+		},
+		{
+			name: "invalid time",
+			data: map[string]string{"id": "a", "time": "yesterday", "releases": `{}`},
+			err:  true,
+		},
+		{
+			name: "invalid releases",
+			data: map[string]string{"id": "a", "time": recordTime.Format(time.RFC3339Nano), "releases": `[`},
+			err:  true,
+		},
+		{
+			name: "missing releases",
+			data: map[string]string{"id": "a", "time": recordTime.Format(time.RFC3339Nano)},
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record, err := parseApplyRecord(test.data)
+
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %#v", record)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(record, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, record)
+			}
+		})
+	}
+}
+
+func TestApplyRecordReleaseList(t *testing.T) {
+	record := &ApplyRecord{Releases: map[string]*builder.ReleaseRevision{
+		"web": {Namespace: "default", Revision: 7},
+		"api": {Namespace: "default", Revision: 3},
+	}}
+
+	if list := record.ReleaseList(); list != "api:3, web:7" {
+		t.Errorf("expected api:3, web:7, got %s", list)
+	}
+}
+
+func TestRollbackTarget(t *testing.T) {
+	history := []*ApplyRecord{
+		{ID: "rollback-2", Action: HISTORY_ACTION_ROLLBACK},
+		{ID: "apply-3", Action: HISTORY_ACTION_APPLY},
+		{ID: "rollback-1", Action: HISTORY_ACTION_ROLLBACK},
+		{ID: "apply-2", Action: HISTORY_ACTION_APPLY},
+		{ID: "apply-1", Action: HISTORY_ACTION_APPLY},
+	}
+
+	tests := []struct {
+		name     string
+		history  []*ApplyRecord
+		id       string
+		expected string
+		err      string
+	}{
+		{name: "apply before the latest apply", history: history, expected: "apply-2"},
+		{name: "by id", history: history, id: "apply-1", expected: "apply-1"},
+		{name: "rollback by id", history: history, id: "rollback-1", expected: "rollback-1"},
+		{name: "unknown id", history: history, id: "apply-9", err: "Apply apply-9 not found"},
+		{name: "single apply", history: history[:3], err: "No earlier apply"},
+		{name: "no history", err: "No earlier apply"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target, err := rollbackTarget(test.history, test.id)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if target.ID != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, target.ID)
+			}
+		})
+	}
+}
+
+func TestHistoryFiltersEnvironment(t *testing.T) {
+	recordTime := time.Date(2022, 11, 3, 14, 0, 0, 0, time.UTC)
+
+	record := func(id string, project string, environment string, offset time.Duration) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      HISTORY_PREFIX + id,
+				Namespace: "default",
+				Labels:    map[string]string{HISTORY_LABEL: project},
+			},
+			Data: map[string]string{
+				"id":          id,
+				"time":        recordTime.Add(offset).Format(time.RFC3339Nano),
+				"action":      HISTORY_ACTION_APPLY,
+				"environment": environment,
+				"releases":    `{}`,
+			},
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		record("production-1", "web", "production", 0),
+		record("production-2", "web", "production", time.Minute),
+		record("staging-1", "web", "staging", 2*time.Minute),
+		record("default-1", "web", "", 3*time.Minute),
+		record("other-1", "other", "production", 4*time.Minute),
+	)
+
+	tests := []struct {
+		environment string
+		expected    []string
+	}{
+		{environment: "production", expected: []string{"production-2", "production-1"}},
+		{environment: "staging", expected: []string{"staging-1"}},
+		{environment: "", expected: []string{"default-1"}},
+		{environment: "development", expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.environment, func(t *testing.T) {
+			p := &ProjectConfig{
+				Project:     &symbiosis.Project{Name: "web"},
+				Clientset:   clientset,
+				commandOpts: &symcommand.CommandOpts{Logger: zerolog.Nop(), Namespace: "default", Environment: test.environment},
+			}
+
+			history, err := p.History()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+
+			for _, record := range history {
+				ids = append(ids, record.ID)
+			}
+
+			if !reflect.DeepEqual(ids, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package project
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// Hooks are shell commands run around the build and deploy stages. They run from the directory of sym.yaml
+// with the deployment context exported as SYM_* environment variables, and KUBECONFIG once a cluster
+// identity exists. onFailure also receives the error in SYM_ERROR.
+type Hooks struct {
+	PreBuild   []string `yaml:"preBuild,omitempty"`
+	PreDeploy  []string `yaml:"preDeploy,omitempty"`
+	PostDeploy []string `yaml:"postDeploy,omitempty"`
+	OnFailure  []string `yaml:"onFailure,omitempty"`
+}
+
+const (
+	HOOK_PRE_BUILD   = "preBuild"
+	HOOK_PRE_DEPLOY  = "preDeploy"
+	HOOK_POST_DEPLOY = "postDeploy"
+	HOOK_ON_FAILURE  = "onFailure"
+
+	// only the last lines of the output of a hook are kept in its result
+	HOOK_OUTPUT_LINES = 100
+)
+
+// HookResult is the outcome of a single hook command
+type HookResult struct {
+	Hook       string    `json:"hook"`
+	Command    string    `json:"command"`
+	Output     []string  `json:"output,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// HookRecorder receives the result of every hook command once it finished
+type HookRecorder func(result *HookResult)
+
+// SetHookRecorder sets a recorder for the results of the hooks, for example to keep them in the run state
+func (p *ProjectConfig) SetHookRecorder(recorder HookRecorder) {
+	p.hookRecorder = recorder
+}
+
+func (p *ProjectConfig) runHooks(hook string, commands []string, env ...string) error {
+	for _, command := range commands {
+		p.commandOpts.Logger.Info().Msgf("Running %s hook: %s", hook, command)
+
+		result := &HookResult{Hook: hook, Command: command, StartedAt: time.Now().UTC()}
+
+		err := p.runHook(result, env)
+
+		result.FinishedAt = time.Now().UTC()
+
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		if p.hookRecorder != nil {
+			p.hookRecorder(result)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s hook %q failed: %v", hook, command, err)
+		}
+	}
+
+	return nil
+}
+
+// runHook runs a single hook command and writes its output to the log line by line. The last lines are
+// also kept in the result.
+func (p *ProjectConfig) runHook(result *HookResult, env []string) error {
+	hook := result.Hook
+
+	reader, writer := io.Pipe()
+
+	cmd := exec.Command("sh", "-c", result.Command)
+	cmd.Dir = filepath.Dir(p.Path)
+	cmd.Env = append(append(os.Environ(), p.hookEnv(hook)...), env...)
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		scanner := bufio.NewScanner(reader)
+
+		for scanner.Scan() {
+			p.commandOpts.Logger.Info().Msgf("[%s] %s", hook, scanner.Text())
+
+			result.Output = append(result.Output, scanner.Text())
+
+			if len(result.Output) > HOOK_OUTPUT_LINES {
+				result.Output = result.Output[1:]
+			}
+		}
+
+		// drain the pipe so the command never blocks on a line too long to scan
+		io.Copy(io.Discard, reader)
+	}()
+
+	err := cmd.Run()
+
+	writer.Close()
+	<-done
+
+	return err
+}
+
+func (p *ProjectConfig) hookEnv(hook string) []string {
+	env := []string{
+		"SYM_HOOK=" + hook,
+		"SYM_CLUSTER=" + p.commandOpts.Cluster,
+		"SYM_NAMESPACE=" + p.commandOpts.Namespace,
+		"SYM_ENVIRONMENT=" + p.commandOpts.Environment,
+	}
+
+	if p.Project != nil {
+		env = append(env, "SYM_PROJECT="+p.Project.Name)
+	}
+
+	if p.identity != nil {
+		env = append(env, "SYM_KUBECONFIG="+p.identity.KubeConfigPath, "KUBECONFIG="+p.identity.KubeConfigPath)
+	}
+
+	return env
+}
+
+// RunFailureHooks runs the onFailure hooks, their errors are logged so the original error is kept. The hooks
+// run at most once, so a stage that already ran them is not reported twice by the caller. Nothing runs when
+// sym.yaml could not be parsed, as there are no hooks to run yet.
+func (p *ProjectConfig) RunFailureHooks(cause error) {
+	if p.failureHooksRun || p.Hooks == nil {
+		return
+	}
+
+	p.failureHooksRun = true
+
+	err := p.runHooks(HOOK_ON_FAILURE, p.Hooks.OnFailure, "SYM_ERROR="+cause.Error())
+
+	if err != nil {
+		p.commandOpts.Logger.Error().Msg(err.Error())
+	}
+}
@@ -0,0 +1,161 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/symbiosis-cloud/cli/pkg/identity"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+func TestHookEnv(t *testing.T) {
+	opts := &symcommand.CommandOpts{Cluster: "prod-1", Namespace: "web", Environment: "production"}
+	base := []string{"SYM_HOOK=preDeploy", "SYM_CLUSTER=prod-1", "SYM_NAMESPACE=web", "SYM_ENVIRONMENT=production"}
+
+	tests := []struct {
+		name     string
+		project  *symbiosis.Project
+		identity *identity.ClusterIdentity
+		expected []string
+	}{
+		{name: "before the project is known", expected: base},
+		{name: "project", project: &symbiosis.Project{Name: "shop"}, expected: append(base[:4:4], "SYM_PROJECT=shop")},
+		{
+			name:     "cluster identity",
+			project:  &symbiosis.Project{Name: "shop"},
+			identity: &identity.ClusterIdentity{KubeConfigPath: "/tmp/kubeconfig"},
+			expected: append(base[:4:4], "SYM_PROJECT=shop", "SYM_KUBECONFIG=/tmp/kubeconfig", "KUBECONFIG=/tmp/kubeconfig"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &ProjectConfig{Project: test.project, identity: test.identity, commandOpts: opts}
+
+			if env := p.hookEnv(HOOK_PRE_DEPLOY); !reflect.DeepEqual(env, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, env)
+			}
+		})
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	dir := t.TempDir()
+
+	var lines []string
+
+	for i := 51; i <= 150; i++ {
+		lines = append(lines, strconv.Itoa(i))
+	}
+
+	tests := []struct {
+		name     string
+		commands []string
+		env      []string
+		outputs  [][]string
+		err      string
+	}{
+		{name: "stdout and stderr", commands: []string{"echo out; echo err >&2"}, outputs: [][]string{{"out", "err"}}},
+		{name: "deployment context", commands: []string{"echo $SYM_HOOK $SYM_PROJECT $SYM_ERROR"}, env: []string{"SYM_ERROR=boom"}, outputs: [][]string{{"preBuild shop boom"}}},
+		{name: "directory of sym.yaml", commands: []string{"pwd"}, outputs: [][]string{{dir}}},
+		{name: "last lines are kept", commands: []string{"seq 1 150"}, outputs: [][]string{lines}},
+		{name: "no output", commands: []string{"true"}, outputs: [][]string{nil}},
+		{
+			name:     "failure stops the remaining commands",
+			commands: []string{"echo first", "echo failing; exit 3", "echo skipped"},
+			outputs:  [][]string{{"first"}, {"failing"}},
+			err:      `preBuild hook "echo failing; exit 3" failed: exit status 3`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var recorded []*HookResult
+
+			p := &ProjectConfig{
+				Path:        filepath.Join(dir, "sym.yaml"),
+				Project:     &symbiosis.Project{Name: "shop"},
+				commandOpts: &symcommand.CommandOpts{Logger: zerolog.Nop()},
+			}
+
+			p.SetHookRecorder(func(result *HookResult) {
+				recorded = append(recorded, result)
+			})
+
+			err := p.runHooks(HOOK_PRE_BUILD, test.commands, test.env...)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(recorded) != len(test.outputs) {
+				t.Fatalf("expected %d results, got %d", len(test.outputs), len(recorded))
+			}
+
+			for i, result := range recorded {
+				if result.Hook != HOOK_PRE_BUILD || result.Command != test.commands[i] {
+					t.Errorf("expected %s hook %q, got %s hook %q", HOOK_PRE_BUILD, test.commands[i], result.Hook, result.Command)
+				}
+
+				if !reflect.DeepEqual(result.Output, test.outputs[i]) {
+					t.Errorf("expected output %v, got %v", test.outputs[i], result.Output)
+				}
+
+				if result.FinishedAt.Before(result.StartedAt) {
+					t.Errorf("expected the hook to finish after it started")
+				}
+			}
+
+			if last := recorded[len(recorded)-1]; test.err != "" && last.Error != "exit status 3" {
+				t.Errorf("expected the failed result to keep the error, got %q", last.Error)
+			}
+		})
+	}
+}
+
+func TestRunFailureHooksOnce(t *testing.T) {
+	p := &ProjectConfig{
+		Path:        filepath.Join(t.TempDir(), "sym.yaml"),
+		Hooks:       &Hooks{OnFailure: []string{"echo $SYM_ERROR"}},
+		commandOpts: &symcommand.CommandOpts{Logger: zerolog.Nop()},
+	}
+
+	var recorded []*HookResult
+
+	p.SetHookRecorder(func(result *HookResult) {
+		recorded = append(recorded, result)
+	})
+
+	p.RunFailureHooks(fmt.Errorf("deploy failed"))
+	p.RunFailureHooks(fmt.Errorf("deploy failed again"))
+
+	if len(recorded) != 1 || !reflect.DeepEqual(recorded[0].Output, []string{"deploy failed"}) {
+		t.Errorf("expected one onFailure run with the first error, got %#v", recorded)
+	}
+}
+
+func TestRunFailureHooksWithoutParsedConfig(t *testing.T) {
+	p := &ProjectConfig{commandOpts: &symcommand.CommandOpts{Logger: zerolog.Nop()}}
+
+	var recorded []*HookResult
+
+	p.SetHookRecorder(func(result *HookResult) {
+		recorded = append(recorded, result)
+	})
+
+	p.RunFailureHooks(fmt.Errorf("Could not parse sym.yaml"))
+
+	if len(recorded) != 0 {
+		t.Errorf("expected no hooks to run, got %d", len(recorded))
+	}
+}
@@ -0,0 +1,445 @@
+package project
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/symbiosis-cloud/cli/pkg/util"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	JOB_LABEL           = "symbiosis.host/job"
+	DEFAULT_JOB_TIMEOUT = 10 * time.Minute
+	MAX_JOB_NAME_LENGTH = 40
+)
+
+var jobNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Job is a one-off Kubernetes Job, such as a database migration, run before or after a Helm deployment.
+// Jobs without before or after run before any deployment starts. Secrets are project secrets exposed
+// as environment variables of the same name. Retention keeps finished Jobs for the given duration,
+// by default they are deleted as soon as they finished.
+type Job struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Image     string            `yaml:"image"`
+	Command   []string          `yaml:"command,omitempty"`
+	Env       map[string]string `yaml:"env,omitempty"`
+	Secrets   []string          `yaml:"secrets,omitempty"`
+	Before    string            `yaml:"before,omitempty"`
+	After     string            `yaml:"after,omitempty"`
+	Timeout   string            `yaml:"timeout,omitempty"`
+	Retention string            `yaml:"retention,omitempty"`
+}
+
+func (j *Job) validate() error {
+	if !jobNamePattern.MatchString(j.Name) || len(j.Name) > MAX_JOB_NAME_LENGTH {
+		return fmt.Errorf("Job name %q must be a lowercase DNS label of at most %d characters", j.Name, MAX_JOB_NAME_LENGTH)
+	}
+
+	if j.Image == "" {
+		return fmt.Errorf("Image is required for job %s", j.Name)
+	}
+
+	if j.Before != "" && j.After != "" {
+		return fmt.Errorf("Job %s can only run before or after a deployment, not both", j.Name)
+	}
+
+	if _, err := j.timeout(); err != nil {
+		return err
+	}
+
+	if _, err := j.retention(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (j *Job) timeout() (time.Duration, error) {
+	if j.Timeout == "" {
+		return DEFAULT_JOB_TIMEOUT, nil
+	}
+
+	timeout, err := time.ParseDuration(j.Timeout)
+
+	if err != nil {
+		return 0, fmt.Errorf("Invalid timeout %q for job %s", j.Timeout, j.Name)
+	}
+
+	return timeout, nil
+}
+
+func (j *Job) retention() (time.Duration, error) {
+	if j.Retention == "" {
+		return 0, nil
+	}
+
+	retention, err := time.ParseDuration(j.Retention)
+
+	if err != nil || retention < 0 {
+		return 0, fmt.Errorf("Invalid retention %q for job %s", j.Retention, j.Name)
+	}
+
+	return retention, nil
+}
+
+// validateJobDeployment checks that before and after reference a Helm deployment
+func (p *ProjectConfig) validateJobDeployment(job *Job) error {
+	for _, name := range []string{job.Before, job.After} {
+		if name == "" {
+			continue
+		}
+
+		if p.Deploy == nil || !hasHelmDeployment(p.Deploy.Helm, name) {
+			return fmt.Errorf("Job %s references unknown Helm deployment %s", job.Name, name)
+		}
+	}
+
+	return nil
+}
+
+// validateJobs checks all jobs before anything is deployed
+func (p *ProjectConfig) validateJobs() error {
+	names := map[string]bool{}
+
+	for i := range p.Jobs {
+		job := &p.Jobs[i]
+
+		err := job.validate()
+
+		if err != nil {
+			return err
+		}
+
+		if names[job.Name] {
+			return fmt.Errorf("Duplicate job name %s", job.Name)
+		}
+
+		names[job.Name] = true
+
+		err = p.validateJobDeployment(job)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jobNamespace returns the namespace of a job, falling back to --namespace
+func (p *ProjectConfig) jobNamespace(job *Job) string {
+	if job.Namespace != "" {
+		return job.Namespace
+	}
+
+	return p.commandOpts.Namespace
+}
+
+// runJobs runs the jobs matching before and after one by one
+func (p *ProjectConfig) runJobs(before string, after string) error {
+	for i := range p.Jobs {
+		job := &p.Jobs[i]
+
+		if job.Before != before || job.After != after {
+			continue
+		}
+
+		err := p.runJob(job)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *ProjectConfig) runJob(job *Job) error {
+	clientset, err := p.clientset()
+
+	if err != nil {
+		return err
+	}
+
+	timeout, err := job.timeout()
+
+	if err != nil {
+		return err
+	}
+
+	retention, err := job.retention()
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+	defer cancel()
+
+	namespace := p.jobNamespace(job)
+	name := fmt.Sprintf("%s-%s", job.Name, time.Now().UTC().Format("20060102150405"))
+	labels := map[string]string{
+		MANAGED_BY_LABEL: MANAGED_BY,
+		JOB_LABEL:        job.Name,
+	}
+
+	p.commandOpts.Logger.Info().Msgf("Running job %s (%s)", job.Name, name)
+
+	env, secret, err := p.jobEnv(job, name, labels)
+
+	if err != nil {
+		return err
+	}
+
+	if secret != nil {
+		secret, err = clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	backoffLimit := int32(0)
+
+	spec := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:    "job",
+							Image:   job.Image,
+							Command: job.Command,
+							Env:     env,
+						},
+					},
+					RestartPolicy: v1.RestartPolicyNever,
+				},
+			},
+		},
+	}
+
+	if retention > 0 {
+		ttl := int32(retention.Seconds())
+		spec.Spec.TTLSecondsAfterFinished = &ttl
+	}
+
+	created, err := clientset.BatchV1().Jobs(namespace).Create(ctx, spec, metav1.CreateOptions{})
+
+	if err != nil {
+		if secret != nil {
+			p.deleteJobSecret(clientset, namespace, secret.Name)
+		}
+
+		return err
+	}
+
+	// registered before anything else can fail, so the job never outlives a failed run
+	if retention == 0 {
+		defer p.deleteJob(clientset, namespace, name)
+	}
+
+	// the secret is garbage collected together with the job
+	if secret != nil {
+		secret.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(created, batchv1.SchemeGroupVersion.WithKind("Job")),
+		}
+
+		_, err = clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+
+		if err != nil {
+			// without the owner reference the secret is not removed with the job
+			p.deleteJobSecret(clientset, namespace, secret.Name)
+
+			if retention > 0 {
+				p.deleteJob(clientset, namespace, name)
+			}
+
+			return err
+		}
+	}
+
+	exitCode, err := p.waitForJob(ctx, clientset, namespace, job.Name, name)
+
+	if err != nil {
+		return fmt.Errorf("Job %s failed: %v", job.Name, err)
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("Job %s failed with exit code %d", job.Name, exitCode)
+	}
+
+	p.commandOpts.Logger.Info().Msgf("Job %s finished", job.Name)
+
+	return nil
+}
+
+// jobEnv returns the environment of a job. Secret values are stored in a Kubernetes secret instead of the job spec.
+func (p *ProjectConfig) jobEnv(job *Job, name string, labels map[string]string) ([]v1.EnvVar, *v1.Secret, error) {
+	var env []v1.EnvVar
+
+	keys := make([]string, 0, len(job.Env))
+
+	for key := range job.Env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		env = append(env, v1.EnvVar{Name: key, Value: job.Env[key]})
+	}
+
+	if len(job.Secrets) == 0 {
+		return env, nil, nil
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		StringData: map[string]string{},
+	}
+
+	for _, secretName := range job.Secrets {
+		value, err := p.secret(secretName)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		secret.StringData[secretName] = value
+
+		env = append(env, v1.EnvVar{
+			Name: secretName,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: name},
+					Key:                  secretName,
+				},
+			},
+		})
+	}
+
+	return env, secret, nil
+}
+
+// waitForJob streams the logs of the job pod to the log and returns the exit code of its container
+func (p *ProjectConfig) waitForJob(ctx context.Context, clientset kubernetes.Interface, namespace string, jobName string, name string) (int32, error) {
+	podsApi := clientset.CoreV1().Pods(namespace)
+	selector := fmt.Sprintf("job-name=%s", name)
+
+	streamed := false
+
+	for {
+		pods, err := podsApi.List(ctx, metav1.ListOptions{LabelSelector: selector})
+
+		if err != nil {
+			return 0, err
+		}
+
+		for _, pod := range pods.Items {
+			for _, status := range pod.Status.ContainerStatuses {
+				if waiting := status.State.Waiting; waiting != nil {
+					switch waiting.Reason {
+					case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+						return 0, fmt.Errorf("%s: %s", waiting.Reason, waiting.Message)
+					}
+				}
+
+				// following the logs blocks until the container exits
+				if (status.State.Running != nil || status.State.Terminated != nil) && !streamed {
+					streamed = true
+					p.streamJobLogs(ctx, podsApi, jobName, pod.Name)
+				}
+
+				if terminated := status.State.Terminated; terminated != nil {
+					return terminated.ExitCode, nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("Timeout waiting for job to finish")
+		case <-time.After(time.Second * 2):
+		}
+	}
+}
+
+func (p *ProjectConfig) streamJobLogs(ctx context.Context, podsApi corev1.PodInterface, jobName string, podName string) {
+	logs, err := podsApi.GetLogs(podName, &v1.PodLogOptions{Follow: true}).Stream(ctx)
+
+	if err != nil {
+		p.commandOpts.Logger.Warn().Msgf("Could not stream logs of job %s: %v", jobName, err)
+		return
+	}
+	defer logs.Close()
+
+	scanner := bufio.NewScanner(logs)
+
+	for scanner.Scan() {
+		p.commandOpts.Logger.Info().Msgf("[%s] %s", jobName, scanner.Text())
+	}
+}
+
+// deleteJob removes a finished job together with its pods and secret
+func (p *ProjectConfig) deleteJob(clientset kubernetes.Interface, namespace string, name string) {
+	propagation := metav1.DeletePropagationBackground
+
+	err := clientset.BatchV1().Jobs(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+
+	if err != nil {
+		p.commandOpts.Logger.Warn().Msgf("Could not delete job %s: %v", name, err)
+	}
+}
+
+func (p *ProjectConfig) deleteJobSecret(clientset kubernetes.Interface, namespace string, name string) {
+	err := clientset.CoreV1().Secrets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+
+	if err != nil {
+		p.commandOpts.Logger.Warn().Msgf("Could not delete job secret %s: %v", name, err)
+	}
+}
+
+// clientset returns the Kubernetes client of the cluster identity, creating it when the identity was set after construction
+func (p *ProjectConfig) clientset() (kubernetes.Interface, error) {
+	if p.Clientset != nil {
+		return p.Clientset, nil
+	}
+
+	if p.identity == nil {
+		return nil, fmt.Errorf("No cluster identity available to run jobs")
+	}
+
+	clientset, err := util.GetKubernetesClient(p.identity.KubeConfigPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.Clientset = clientset
+
+	return clientset, nil
+}
@@ -0,0 +1,240 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testJobProject(clientset *fake.Clientset) *ProjectConfig {
+	return &ProjectConfig{
+		Project:     &symbiosis.Project{Name: "web"},
+		Clientset:   clientset,
+		secrets:     symbiosis.SecretCollection{"DB_PASSWORD": {Value: "hunter2"}},
+		commandOpts: &symcommand.CommandOpts{Logger: zerolog.Nop(), Namespace: "default"},
+	}
+}
+
+// startJobPods adds a pod with the given container state for every created job, like the job controller would
+func startJobPods(clientset *fake.Clientset, state v1.ContainerState) {
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      job.Name + "-pod",
+				Namespace: job.Namespace,
+				Labels:    map[string]string{"job-name": job.Name},
+			},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "job", State: state}}},
+		}
+
+		return false, nil, clientset.Tracker().Add(pod)
+	})
+}
+
+// failOn makes every request with the verb on the resource fail
+func failOn(clientset *fake.Clientset, verb string, resource string) {
+	clientset.PrependReactor(verb, resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("%s %s refused", verb, resource)
+	})
+}
+
+// jobActions returns the changes made to jobs and secrets in order
+func jobActions(clientset *fake.Clientset) []string {
+	var actions []string
+
+	for _, action := range clientset.Actions() {
+		resource := action.GetResource().Resource
+
+		if action.GetVerb() == "list" || action.GetVerb() == "get" || (resource != "jobs" && resource != "secrets") {
+			continue
+		}
+
+		actions = append(actions, action.GetVerb()+" "+resource)
+	}
+
+	return actions
+}
+
+func TestJobEnv(t *testing.T) {
+	p := testJobProject(fake.NewSimpleClientset())
+	labels := map[string]string{JOB_LABEL: "migrate"}
+
+	env, secret, err := p.jobEnv(&Job{Name: "migrate", Env: map[string]string{"MODE": "up", "DEBUG": "1"}}, "migrate-1", labels)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []v1.EnvVar{{Name: "DEBUG", Value: "1"}, {Name: "MODE", Value: "up"}}
+
+	if !reflect.DeepEqual(env, expected) || secret != nil {
+		t.Errorf("expected sorted plain env %v without secret, got %v and %v", expected, env, secret)
+	}
+
+	env, secret, err = p.jobEnv(&Job{Name: "migrate", Secrets: []string{"DB_PASSWORD"}}, "migrate-1", labels)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if secret == nil || secret.Name != "migrate-1" || secret.StringData["DB_PASSWORD"] != "hunter2" || !reflect.DeepEqual(secret.Labels, labels) {
+		t.Fatalf("expected secret migrate-1 holding the value, got %#v", secret)
+	}
+
+	if len(env) != 1 || env[0].Value != "" || env[0].ValueFrom.SecretKeyRef.Name != "migrate-1" || env[0].ValueFrom.SecretKeyRef.Key != "DB_PASSWORD" {
+		t.Errorf("expected the secret value to be referenced instead of inlined, got %#v", env)
+	}
+
+	_, _, err = p.jobEnv(&Job{Name: "migrate", Secrets: []string{"MISSING"}}, "migrate-1", labels)
+
+	if err == nil || !strings.Contains(err.Error(), "Secret MISSING could not be found") {
+		t.Errorf("expected a missing secret error, got %v", err)
+	}
+}
+
+func TestRunJob(t *testing.T) {
+	succeeded := v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}
+
+	tests := []struct {
+		name      string
+		job       Job
+		state     v1.ContainerState
+		fail      []string
+		err       string
+		actions   []string
+		remaining int
+	}{
+		{
+			name:    "success",
+			job:     Job{Name: "migrate", Image: "app", Secrets: []string{"DB_PASSWORD"}},
+			state:   succeeded,
+			actions: []string{"create secrets", "create jobs", "update secrets", "delete jobs"},
+		},
+		{
+			name:      "retention keeps the job",
+			job:       Job{Name: "migrate", Image: "app", Retention: "1h"},
+			state:     succeeded,
+			actions:   []string{"create jobs"},
+			remaining: 1,
+		},
+		{
+			name:    "exit code",
+			job:     Job{Name: "migrate", Image: "app"},
+			state:   v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 3}},
+			err:     "Job migrate failed with exit code 3",
+			actions: []string{"create jobs", "delete jobs"},
+		},
+		{
+			name:    "image pull failure",
+			job:     Job{Name: "migrate", Image: "missing"},
+			state:   v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}},
+			err:     "Job migrate failed: ImagePullBackOff: not found",
+			actions: []string{"create jobs", "delete jobs"},
+		},
+		{
+			name:    "job create failure removes the secret",
+			job:     Job{Name: "migrate", Image: "app", Secrets: []string{"DB_PASSWORD"}},
+			fail:    []string{"create", "jobs"},
+			err:     "create jobs refused",
+			actions: []string{"create secrets", "create jobs", "delete secrets"},
+		},
+		{
+			name:    "secret owner failure removes secret and job",
+			job:     Job{Name: "migrate", Image: "app", Secrets: []string{"DB_PASSWORD"}},
+			fail:    []string{"update", "secrets"},
+			err:     "update secrets refused",
+			actions: []string{"create secrets", "create jobs", "update secrets", "delete secrets", "delete jobs"},
+		},
+		{
+			name:    "secret owner failure with retention removes secret and job",
+			job:     Job{Name: "migrate", Image: "app", Secrets: []string{"DB_PASSWORD"}, Retention: "1h"},
+			fail:    []string{"update", "secrets"},
+			err:     "update secrets refused",
+			actions: []string{"create secrets", "create jobs", "update secrets", "delete secrets", "delete jobs"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			startJobPods(clientset, test.state)
+
+			if test.fail != nil {
+				failOn(clientset, test.fail[0], test.fail[1])
+			}
+
+			err := testJobProject(clientset).runJob(&test.job)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actions := jobActions(clientset); !reflect.DeepEqual(actions, test.actions) {
+				t.Errorf("expected actions %v, got %v", test.actions, actions)
+			}
+
+			jobs, err := clientset.BatchV1().Jobs("default").List(context.TODO(), metav1.ListOptions{})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(jobs.Items) != test.remaining {
+				t.Errorf("expected %d remaining jobs, got %d", test.remaining, len(jobs.Items))
+			}
+		})
+	}
+}
+
+func TestRunJobSecretOwnership(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	startJobPods(clientset, v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}})
+
+	err := testJobProject(clientset).runJob(&Job{Name: "migrate", Image: "app", Secrets: []string{"DB_PASSWORD"}, Retention: "1h"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := clientset.BatchV1().Jobs("default").List(context.TODO(), metav1.ListOptions{})
+
+	if err != nil || len(jobs.Items) != 1 {
+		t.Fatalf("expected one job, got %v and %v", jobs, err)
+	}
+
+	job := jobs.Items[0]
+
+	if job.Spec.TTLSecondsAfterFinished == nil || *job.Spec.TTLSecondsAfterFinished != 3600 {
+		t.Errorf("expected a ttl of 3600 seconds, got %v", job.Spec.TTLSecondsAfterFinished)
+	}
+
+	secret, err := clientset.CoreV1().Secrets("default").Get(context.TODO(), job.Name, metav1.GetOptions{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner := metav1.GetControllerOf(secret)
+
+	if owner == nil || owner.Kind != "Job" || owner.Name != job.Name {
+		t.Errorf("expected the secret to be owned by job %s, got %#v", job.Name, owner)
+	}
+}
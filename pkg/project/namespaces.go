@@ -0,0 +1,150 @@
+package project
+
+import (
+	"context"
+	"sort"
+
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	MANAGED_BY_LABEL = "app.kubernetes.io/managed-by"
+	MANAGED_BY       = "sym-cli"
+)
+
+// Namespace sets the labels and annotations of a namespace created by sym. Existing namespaces are left untouched.
+type Namespace struct {
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// DeploymentNamespaces returns the namespaces targeted by the deployments and jobs, sorted and without duplicates
+func (p *ProjectConfig) DeploymentNamespaces() []string {
+	namespaces := []string{p.commandOpts.Namespace}
+
+	for _, b := range p.builders {
+		namespaces = append(namespaces, b.Namespaces()...)
+	}
+
+	for _, job := range p.Jobs {
+		namespaces = append(namespaces, p.jobNamespace(&job))
+	}
+
+	return uniqueNamespaces(namespaces)
+}
+
+// HasNamespaceOverrides reports whether any deployment, job or test sets its own namespace
+func (p *ProjectConfig) HasNamespaceOverrides() bool {
+	if p.Deploy != nil {
+		for _, d := range p.Deploy.Helm {
+			if d.Namespace != "" {
+				return true
+			}
+		}
+
+		for _, d := range p.Deploy.Kustomize {
+			if d.Namespace != "" {
+				return true
+			}
+		}
+	}
+
+	for _, job := range p.Jobs {
+		if job.Namespace != "" {
+			return true
+		}
+	}
+
+	for _, test := range p.Test {
+		if test.Namespace != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ensureNamespaces creates the given namespaces and all namespaces configured in sym.yaml when they do not exist yet
+func (p *ProjectConfig) ensureNamespaces(namespaces []string) error {
+	clientset, err := p.clientset()
+
+	if err != nil {
+		return err
+	}
+
+	for name := range p.Namespaces {
+		namespaces = append(namespaces, name)
+	}
+
+	namespacesApi := clientset.CoreV1().Namespaces()
+
+	for _, name := range uniqueNamespaces(namespaces) {
+		_, err := namespacesApi.Get(context.TODO(), name, metav1.GetOptions{})
+
+		if err == nil {
+			continue
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+
+		p.commandOpts.Logger.Info().Msgf("Creating namespace %s", name)
+
+		namespace := &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+		}
+
+		if config := p.Namespaces[name]; config != nil {
+			for key, value := range config.Labels {
+				namespace.Labels[key] = value
+			}
+
+			for key, value := range config.Annotations {
+				namespace.Annotations[key] = value
+			}
+		}
+
+		// destroy only deletes namespaces created for the same project and environment
+		namespace.Labels[MANAGED_BY_LABEL] = MANAGED_BY
+		namespace.Labels[builder.PROJECT_LABEL] = builder.LabelValue(p.Project.Name)
+		namespace.Annotations[builder.PROJECT_ANNOTATION] = p.Project.Name
+
+		if p.commandOpts.Environment != "" {
+			namespace.Labels[builder.ENVIRONMENT_LABEL] = builder.LabelValue(p.commandOpts.Environment)
+		}
+
+		_, err = namespacesApi.Create(context.TODO(), namespace, metav1.CreateOptions{})
+
+		// another deploy may have created it in the meantime
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func uniqueNamespaces(namespaces []string) []string {
+	seen := map[string]bool{}
+
+	var unique []string
+
+	for _, namespace := range namespaces {
+		if namespace == "" || seen[namespace] {
+			continue
+		}
+
+		seen[namespace] = true
+		unique = append(unique, namespace)
+	}
+
+	sort.Strings(unique)
+
+	return unique
+}
@@ -0,0 +1,139 @@
+package project
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/symcommand"
+	"github.com/symbiosis-cloud/symbiosis-go"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUniqueNamespaces(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespaces []string
+		expected   []string
+	}{
+		{name: "none", namespaces: nil, expected: nil},
+		{name: "only empty", namespaces: []string{"", ""}, expected: nil},
+		{name: "sorted", namespaces: []string{"monitoring", "default"}, expected: []string{"default", "monitoring"}},
+		{name: "duplicates and empty entries", namespaces: []string{"default", "", "app", "default", "app"}, expected: []string{"app", "default"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if unique := uniqueNamespaces(test.namespaces); !reflect.DeepEqual(unique, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, unique)
+			}
+		})
+	}
+}
+
+func TestEnsureNamespaces(t *testing.T) {
+	existing := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "platform"}}}
+
+	tests := []struct {
+		name        string
+		environment string
+		expected    map[string]*v1.Namespace
+	}{
+		{
+			name: "without environment",
+			expected: map[string]*v1.Namespace{
+				"app": {ObjectMeta: metav1.ObjectMeta{
+					Name:        "app",
+					Labels:      map[string]string{MANAGED_BY_LABEL: MANAGED_BY, builder.PROJECT_LABEL: builder.LabelValue("Web Shop")},
+					Annotations: map[string]string{builder.PROJECT_ANNOTATION: "Web Shop"},
+				}},
+				"monitoring": {ObjectMeta: metav1.ObjectMeta{
+					Name: "monitoring",
+					Labels: map[string]string{
+						"istio-injection":     "enabled",
+						MANAGED_BY_LABEL:      MANAGED_BY,
+						builder.PROJECT_LABEL: builder.LabelValue("Web Shop"),
+					},
+					Annotations: map[string]string{"owner": "sre", builder.PROJECT_ANNOTATION: "Web Shop"},
+				}},
+			},
+		},
+		{
+			name:        "with environment",
+			environment: "production",
+			expected: map[string]*v1.Namespace{
+				"app": {ObjectMeta: metav1.ObjectMeta{
+					Name: "app",
+					Labels: map[string]string{
+						MANAGED_BY_LABEL:          MANAGED_BY,
+						builder.PROJECT_LABEL:     builder.LabelValue("Web Shop"),
+						builder.ENVIRONMENT_LABEL: "production",
+					},
+					Annotations: map[string]string{builder.PROJECT_ANNOTATION: "Web Shop"},
+				}},
+				"monitoring": {ObjectMeta: metav1.ObjectMeta{
+					Name: "monitoring",
+					Labels: map[string]string{
+						"istio-injection":         "enabled",
+						MANAGED_BY_LABEL:          MANAGED_BY,
+						builder.PROJECT_LABEL:     builder.LabelValue("Web Shop"),
+						builder.ENVIRONMENT_LABEL: "production",
+					},
+					Annotations: map[string]string{"owner": "sre", builder.PROJECT_ANNOTATION: "Web Shop"},
+				}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(existing.DeepCopy())
+
+			p := &ProjectConfig{
+				Project:   &symbiosis.Project{Name: "Web Shop"},
+				Clientset: clientset,
+				Namespaces: map[string]*Namespace{
+					// ownership labels cannot be overridden by the configuration
+					"monitoring": {
+						Labels:      map[string]string{"istio-injection": "enabled", MANAGED_BY_LABEL: "helm"},
+						Annotations: map[string]string{"owner": "sre"},
+					},
+					"default": {Labels: map[string]string{"team": "web"}},
+				},
+				commandOpts: &symcommand.CommandOpts{Logger: zerolog.Nop(), Environment: test.environment},
+			}
+
+			err := p.ensureNamespaces([]string{"app", "default", "app", ""})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(namespaces.Items) != len(test.expected)+1 {
+				t.Errorf("expected %d namespaces, got %d", len(test.expected)+1, len(namespaces.Items))
+			}
+
+			for _, namespace := range namespaces.Items {
+				expected, ok := test.expected[namespace.Name]
+
+				if !ok {
+					expected = existing
+				}
+
+				if !reflect.DeepEqual(namespace.Labels, expected.Labels) || !reflect.DeepEqual(namespace.Annotations, expected.Annotations) {
+					t.Errorf("namespace %s: expected labels %v and annotations %v, got %v and %v", namespace.Name, expected.Labels, expected.Annotations, namespace.Labels, namespace.Annotations)
+				}
+			}
+		})
+	}
+}
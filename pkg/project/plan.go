@@ -0,0 +1,139 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	"github.com/symbiosis-cloud/cli/pkg/manifest"
+)
+
+const (
+	PLAN_ACTION_ADD    = "add"
+	PLAN_ACTION_CHANGE = "change"
+	PLAN_ACTION_REMOVE = "remove"
+)
+
+// PlannedChange is an object an apply would add, change or remove
+type PlannedChange struct {
+	Builder   string   `json:"builder"`
+	Source    string   `json:"source"`
+	Kind      string   `json:"kind"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Action    string   `json:"action"`
+	Fields    []string `json:"fields,omitempty"`
+}
+
+// Plan renders every builder and compares the result with the live objects of the cluster. Objects of a
+// release that are no longer rendered are planned for removal.
+func (p *ProjectConfig) Plan() ([]*PlannedChange, error) {
+	manifests, client, err := p.renderManifests()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*PlannedChange
+
+	for _, m := range manifests {
+		desired, err := manifest.Parse(m.Content)
+
+		if err != nil {
+			return nil, err
+		}
+
+		rendered := map[string]bool{}
+
+		for _, object := range desired {
+			live, err := client.Get(context.TODO(), object, m.Namespace)
+
+			if err != nil {
+				return nil, err
+			}
+
+			rendered[manifest.Key(object)] = true
+
+			if live == nil {
+				changes = append(changes, newPlannedChange(m, object.GetKind(), object.GetName(), object.GetNamespace(), PLAN_ACTION_ADD, nil))
+			} else if diffs := manifest.Diff(object, live); len(diffs) > 0 {
+				changes = append(changes, newPlannedChange(m, object.GetKind(), object.GetName(), object.GetNamespace(), PLAN_ACTION_CHANGE, manifest.Paths(diffs)))
+			}
+		}
+
+		previous, err := manifest.Parse(m.Previous)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range previous {
+			err = client.Normalize(object, m.Namespace)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if rendered[manifest.Key(object)] {
+				continue
+			}
+
+			live, err := client.Get(context.TODO(), object, m.Namespace)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if live != nil {
+				changes = append(changes, newPlannedChange(m, object.GetKind(), object.GetName(), object.GetNamespace(), PLAN_ACTION_REMOVE, nil))
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// renderManifests builds and renders every builder without running hooks or deploying anything
+func (p *ProjectConfig) renderManifests() ([]*builder.Manifest, *manifest.Client, error) {
+	if p.identity == nil {
+		return nil, nil, fmt.Errorf("No cluster identity available to compare with")
+	}
+
+	client, err := manifest.NewClient(p.identity.KubeConfigPath)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var manifests []*builder.Manifest
+
+	for _, b := range p.builders {
+		err := b.Build()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		rendered, err := b.Render()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		manifests = append(manifests, rendered...)
+	}
+
+	return manifests, client, nil
+}
+
+func newPlannedChange(m *builder.Manifest, kind string, name string, namespace string, action string, fields []string) *PlannedChange {
+	return &PlannedChange{
+		Builder:   m.Builder,
+		Source:    m.Name,
+		Kind:      kind,
+		Name:      name,
+		Namespace: namespace,
+		Action:    action,
+		Fields:    fields,
+	}
+}
@@ -0,0 +1,120 @@
+package project
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type PreviewStrategy string
+
+const (
+	PREVIEW_STRATEGY_CLUSTER   PreviewStrategy = "cluster"
+	PREVIEW_STRATEGY_NAMESPACE PreviewStrategy = "namespace"
+
+	PREVIEW_LABEL                 = "symbiosis.host/preview"
+	PREVIEW_IDENTIFIER_ANNOTATION = "symbiosis.host/preview-identifier"
+
+	maxPreviewNameLength = 40
+)
+
+var previewSlugRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+type Preview struct {
+	// Strategy decides whether every preview gets its own cluster or its own namespace in Cluster
+	Strategy PreviewStrategy `yaml:"strategy,omitempty"`
+	Cluster  string          `yaml:"cluster,omitempty"`
+	Prefix   string          `yaml:"prefix,omitempty"`
+	Region   string          `yaml:"region,omitempty"`
+	NodeType string          `yaml:"nodeType,omitempty"`
+	Nodes    int             `yaml:"nodes,omitempty"`
+	TTL      string          `yaml:"ttl,omitempty"`
+
+	ttl time.Duration
+}
+
+func (p *Preview) setDefaults() error {
+	if p.Strategy == "" {
+		p.Strategy = PREVIEW_STRATEGY_CLUSTER
+	}
+
+	if p.Prefix == "" {
+		p.Prefix = "preview"
+	}
+
+	if p.Region == "" {
+		p.Region = "germany-1"
+	}
+
+	if p.NodeType == "" {
+		p.NodeType = "general-1"
+	}
+
+	if p.Nodes == 0 {
+		p.Nodes = 1
+	}
+
+	switch p.Strategy {
+	case PREVIEW_STRATEGY_CLUSTER:
+	case PREVIEW_STRATEGY_NAMESPACE:
+		if p.Cluster == "" {
+			return fmt.Errorf("Preview strategy %s requires a cluster to be set", p.Strategy)
+		}
+	default:
+		return fmt.Errorf("Unknown preview strategy %s (valid: %s, %s)", p.Strategy, PREVIEW_STRATEGY_CLUSTER, PREVIEW_STRATEGY_NAMESPACE)
+	}
+
+	if p.TTL != "" {
+		ttl, err := time.ParseDuration(p.TTL)
+
+		if err != nil {
+			return fmt.Errorf("Invalid preview ttl %s: %v", p.TTL, err)
+		}
+
+		p.ttl = ttl
+	}
+
+	return nil
+}
+
+// Name derives a deterministic cluster or namespace name from a branch or PR identifier
+func (p *Preview) Name(identifier string) (string, error) {
+	slug := strings.Trim(previewSlugRegexp.ReplaceAllString(strings.ToLower(identifier), "-"), "-")
+
+	if slug == "" {
+		return "", fmt.Errorf("Cannot derive a preview name from %q", identifier)
+	}
+
+	name := fmt.Sprintf("%s-%s", p.Prefix, slug)
+
+	// keep long branch names unique by suffixing a short hash of the full identifier
+	if len(name) > maxPreviewNameLength {
+		sum := sha1.Sum([]byte(identifier))
+		name = fmt.Sprintf("%s-%x", strings.TrimRight(name[:maxPreviewNameLength-7], "-"), sum[:3])
+	}
+
+	return name, nil
+}
+
+// IsPreview reports whether a cluster or namespace name was derived by Name
+func (p *Preview) IsPreview(name string) bool {
+	return strings.HasPrefix(name, p.Prefix+"-")
+}
+
+// ExpiresAt returns when a preview created at the given time expires. The second return value is
+// false when no ttl has been configured.
+func (p *Preview) ExpiresAt(created time.Time) (time.Time, bool) {
+	if p.ttl == 0 {
+		return time.Time{}, false
+	}
+
+	return created.Add(p.ttl), true
+}
+
+func (p *Preview) IsExpired(created time.Time) bool {
+	expiresAt, ok := p.ExpiresAt(created)
+
+	return ok && time.Now().After(expiresAt)
+}
@@ -0,0 +1,76 @@
+package project
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreviewName(t *testing.T) {
+	preview := &Preview{Prefix: "preview"}
+
+	tests := []struct {
+		name       string
+		identifier string
+		expected   string
+		err        bool
+	}{
+		{name: "branch", identifier: "feature-login", expected: "preview-feature-login"},
+		{name: "slashes and case", identifier: "Feature/Login_Page", expected: "preview-feature-login-page"},
+		{name: "pull request", identifier: "#123", expected: "preview-123"},
+		{name: "leading and trailing separators", identifier: "--fix--", expected: "preview-fix"},
+		{name: "long branch is shortened with a hash", identifier: "feature/a-very-long-branch-name-that-does-not-fit", expected: "preview-feature-a-very-long-branc-982ba1"},
+		{name: "nothing left", identifier: "///", err: true},
+		{name: "empty", identifier: "", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, err := preview.Name(test.identifier)
+
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", name)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if name != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, name)
+			}
+
+			if len(name) > maxPreviewNameLength {
+				t.Errorf("%s is longer than %d characters", name, maxPreviewNameLength)
+			}
+
+			if !preview.IsPreview(name) {
+				t.Errorf("%s is not recognised as a preview", name)
+			}
+		})
+	}
+}
+
+func TestPreviewNameIsUniqueForLongIdentifiers(t *testing.T) {
+	preview := &Preview{Prefix: "preview"}
+	prefix := "feature/" + strings.Repeat("x", 40)
+
+	a, err := preview.Name(prefix + "-a")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := preview.Name(prefix + "-b")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a == b {
+		t.Errorf("expected different names, got %s twice", a)
+	}
+}
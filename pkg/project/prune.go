@@ -0,0 +1,51 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+)
+
+// UndeclaredReleases returns the Helm releases installed for the project that were removed from sym.yaml
+func (p *ProjectConfig) UndeclaredReleases() ([]*builder.ManagedRelease, error) {
+	helm, err := p.pruneBuilder()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return helm.UndeclaredReleases()
+}
+
+// Prune uninstalls releases returned by UndeclaredReleases
+func (p *ProjectConfig) Prune(releases []*builder.ManagedRelease) ([]*builder.DestroyResult, error) {
+	helm, err := p.pruneBuilder()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return helm.Prune(releases)
+}
+
+func (p *ProjectConfig) pruneBuilder() (*builder.HelmBuilder, error) {
+	if p.identity == nil {
+		return nil, fmt.Errorf("No cluster identity available to look up releases")
+	}
+
+	if helm := p.helmBuilder(); helm != nil {
+		return helm, nil
+	}
+
+	// every Helm deployment was removed from sym.yaml, the releases installed before still have to be found
+	helm, err := builder.NewHelmBuilder(nil, filepath.Dir(p.Path), p.Project.Name, p.commandOpts, p.secret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	helm.SetIdentity(p.identity)
+
+	return helm, nil
+}
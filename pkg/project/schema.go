@@ -0,0 +1,84 @@
+package project
+
+import (
+	"reflect"
+	"strings"
+)
+
+const SCHEMA_ID = "https://raw.githubusercontent.com/symbiosis-cloud/cli/main/schema/sym.schema.json"
+
+// Schema generates the JSON Schema of sym.yaml from the yaml tags of ProjectConfig and the types it references
+func Schema() map[string]interface{} {
+	schema := typeSchema(reflect.TypeOf(ProjectConfig{}))
+
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["$id"] = SCHEMA_ID
+	schema["title"] = "sym.yaml"
+
+	return schema
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+
+		for i := 0; i < t.NumField(); i++ {
+			name, ok := yamlFieldName(t.Field(i))
+
+			if !ok {
+				continue
+			}
+
+			properties[name] = typeSchema(t.Field(i).Type)
+		}
+
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": typeSchema(t.Elem()),
+		}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": typeSchema(t.Elem()),
+		}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+
+	// interfaces accept any value
+	return map[string]interface{}{}
+}
+
+// yamlFieldName returns the key of an exported struct field in sym.yaml. Fields without a yaml tag
+// are runtime state and not part of the file format.
+func yamlFieldName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("yaml")
+
+	if !ok || field.PkgPath != "" {
+		return "", false
+	}
+
+	name := strings.Split(tag, ",")[0]
+
+	if name == "-" || name == "" {
+		return "", false
+	}
+
+	return name, true
+}
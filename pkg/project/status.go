@@ -0,0 +1,20 @@
+package project
+
+import "github.com/symbiosis-cloud/cli/pkg/builder"
+
+// Status returns the status of every Helm release and kustomization in sym.yaml
+func (p *ProjectConfig) Status() ([]*builder.DeploymentStatus, error) {
+	var statuses []*builder.DeploymentStatus
+
+	for _, b := range p.builders {
+		status, err := b.Status()
+
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, status...)
+	}
+
+	return statuses, nil
+}
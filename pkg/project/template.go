@@ -0,0 +1,105 @@
+package project
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/symbiosis-cloud/cli/pkg/util"
+	"gopkg.in/yaml.v2"
+)
+
+const REDACTED = "REDACTED"
+
+// templateFuncs returns the functions available in sym.yaml. Relative paths and git lookups are resolved
+// against dir, the directory containing sym.yaml.
+func templateFuncs(dir string, secret func(string) (string, error)) template.FuncMap {
+	return template.FuncMap{
+		"Secret": secret,
+		"Env":    os.Getenv,
+		"EnvOr": func(name string, fallback string) string {
+			return util.GetEnvOrDefault(name, fallback)
+		},
+		"File": func(file string) (string, error) {
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(dir, file)
+			}
+
+			content, err := os.ReadFile(file)
+
+			if err != nil {
+				return "", err
+			}
+
+			return string(content), nil
+		},
+		"default": func(fallback interface{}, value interface{}) interface{} {
+			if isEmpty(value) {
+				return fallback
+			}
+			return value
+		},
+		"required": func(message string, value interface{}) (interface{}, error) {
+			if isEmpty(value) {
+				return nil, errors.New(message)
+			}
+			return value, nil
+		},
+		"b64enc": func(value string) string {
+			return base64.StdEncoding.EncodeToString([]byte(value))
+		},
+		"toYaml": func(value interface{}) (string, error) {
+			output, err := yaml.Marshal(value)
+
+			if err != nil {
+				return "", err
+			}
+
+			return strings.TrimSuffix(string(output), "\n"), nil
+		},
+		"quote": func(value interface{}) string {
+			return strconv.Quote(fmt.Sprint(value))
+		},
+		"GitSHA": func() (string, error) {
+			return util.GitSHA(dir)
+		},
+		"GitBranch": func() (string, error) {
+			return util.GitBranch(dir)
+		},
+		"GitTag": func() (string, error) {
+			return util.GitTag(dir)
+		},
+	}
+}
+
+// offlineFuncs replaces template functions that need the Symbiosis API with placeholders
+func offlineFuncs(dir string) template.FuncMap {
+	return templateFuncs(dir, func(secretName string) (string, error) {
+		return fmt.Sprintf("SECRET_%s", secretName), nil
+	})
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return v.IsZero()
+}
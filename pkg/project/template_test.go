@@ -0,0 +1,106 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "token.txt"), []byte("file-token"), 0600)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SYM_TEST_SET", "from-env")
+
+	secret := func(name string) (string, error) {
+		if name == "db-password" {
+			return "hunter2", nil
+		}
+
+		return "", fmt.Errorf("Secret %s could not be found", name)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		err      string
+	}{
+		{name: "secret", template: `{{ Secret "db-password" }}`, expected: "hunter2"},
+		{name: "unknown secret", template: `{{ Secret "missing" }}`, err: "Secret missing could not be found"},
+		{name: "env", template: `{{ Env "SYM_TEST_SET" }}`, expected: "from-env"},
+		{name: "env or fallback", template: `{{ EnvOr "SYM_TEST_UNSET" "fallback" }}`, expected: "fallback"},
+		{name: "relative file", template: `{{ File "token.txt" }}`, expected: "file-token"},
+		{name: "missing file", template: `{{ File "missing.txt" }}`, err: "missing.txt"},
+		{name: "default for empty value", template: `{{ "" | default "latest" }}`, expected: "latest"},
+		{name: "default keeps value", template: `{{ "v1" | default "latest" }}`, expected: "v1"},
+		{name: "default for zero number", template: `{{ 0 | default 3 }}`, expected: "3"},
+		{name: "required value", template: `{{ "set" | required "value is required" }}`, expected: "set"},
+		{name: "required missing", template: `{{ "" | required "image tag is required" }}`, err: "image tag is required"},
+		{name: "required message is not a format string", template: `{{ "" | required "100% required" }}`, err: "100% required"},
+		{name: "b64enc", template: `{{ b64enc "user:pass" }}`, expected: "dXNlcjpwYXNz"},
+		{name: "quote", template: `{{ quote "a \"b\"" }}`, expected: `"a \"b\""`},
+		{name: "quote number", template: `{{ quote 8080 }}`, expected: `"8080"`},
+		{name: "toYaml", template: `{{ toYaml (Secret "db-password") }}`, expected: "hunter2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, err := renderConfig([]byte(test.template), templateFuncs(dir, secret))
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+
+				if strings.Contains(err.Error(), "%!") {
+					t.Errorf("error contains formatting directives: %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(rendered) != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, rendered)
+			}
+		})
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	var nilPointer *string
+
+	tests := []struct {
+		value interface{}
+		empty bool
+	}{
+		{nil, true},
+		{"", true},
+		{"value", false},
+		{0, true},
+		{1, false},
+		{false, true},
+		{true, false},
+		{[]string{}, true},
+		{[]string{"a"}, false},
+		{map[string]string{}, true},
+		{nilPointer, true},
+	}
+
+	for _, test := range tests {
+		if empty := isEmpty(test.value); empty != test.empty {
+			t.Errorf("isEmpty(%#v): expected %v, got %v", test.value, test.empty, empty)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/symbiosis-cloud/cli/pkg/builder"
+	yamlv2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidationError points at a problem in sym.yaml. Rendered is set when the template changed the number of
+// lines, Line and Column then refer to the output of sym render instead of the source file.
+type ValidationError struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+	Rendered bool   `json:"rendered,omitempty"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Path, e.Message)
+}
+
+type validator struct {
+	file   string
+	dir    string
+	root   *yaml.Node
+	errors []*ValidationError
+}
+
+var yamlLineRegexp = regexp.MustCompile(`line (\d+)`)
+
+// Validate checks a sym.yaml file without contacting the Symbiosis API or a cluster. Problems with the
+// file are returned as validation errors, the error return value is reserved for files that cannot be read.
+func Validate(file string) ([]*ValidationError, error) {
+	filePath, err := filepath.Abs(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	v := &validator{file: file, dir: filepath.Dir(filePath)}
+
+	rendered, err := renderConfig(content, offlineFuncs(v.dir))
+
+	if err != nil {
+		v.add(nil, "", fmt.Sprintf("Template error: %v", err))
+		return v.errors, nil
+	}
+
+	// templates that only replace values keep every line in place, otherwise positions refer to the rendered file
+	shifted := bytes.Count(rendered, []byte("\n")) != bytes.Count(content, []byte("\n"))
+
+	var document yaml.Node
+
+	err = yaml.Unmarshal(rendered, &document)
+
+	if err != nil {
+		syntaxError := &ValidationError{File: file, Line: 1, Column: 1, Path: ".", Message: err.Error(), Rendered: shifted}
+
+		if match := yamlLineRegexp.FindStringSubmatch(err.Error()); match != nil {
+			syntaxError.Line, _ = strconv.Atoi(match[1])
+		}
+
+		return []*ValidationError{syntaxError}, nil
+	}
+
+	if len(document.Content) == 0 {
+		v.add(nil, "", "File is empty")
+		return v.errors, nil
+	}
+
+	v.root = document.Content[0]
+	v.validateNode(v.root, Schema(), "")
+
+	// only check semantics when the structure is sound
+	if len(v.errors) == 0 {
+		var config ProjectConfig
+
+		err = yamlv2.Unmarshal(rendered, &config)
+
+		if err != nil {
+			v.add(v.root, "", err.Error())
+		} else {
+			v.validateConfig(&config)
+		}
+	}
+
+	for _, e := range v.errors {
+		e.Rendered = shifted
+	}
+
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		if v.errors[i].Line == v.errors[j].Line {
+			return v.errors[i].Column < v.errors[j].Column
+		}
+		return v.errors[i].Line < v.errors[j].Line
+	})
+
+	return v.errors, nil
+}
+
+func (v *validator) add(node *yaml.Node, path string, message string) {
+	err := &ValidationError{File: v.file, Line: 1, Column: 1, Path: path, Message: message}
+
+	if node != nil {
+		err.Line = node.Line
+		err.Column = node.Column
+	}
+
+	if err.Path == "" {
+		err.Path = "."
+	}
+
+	v.errors = append(v.errors, err)
+}
+
+func (v *validator) validateNode(node *yaml.Node, schema map[string]interface{}, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	// empty values are treated as omitted
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	switch schema["type"] {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			v.add(node, path, "Expected a mapping")
+			return
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			value := node.Content[i+1]
+			keyPath := strings.TrimPrefix(fmt.Sprintf("%s.%s", path, key.Value), ".")
+
+			if property, ok := properties[key.Value].(map[string]interface{}); ok {
+				v.validateNode(value, property, keyPath)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				v.validateNode(value, additional, keyPath)
+			} else {
+				v.add(key, keyPath, fmt.Sprintf("Unknown field %s", key.Value))
+			}
+		}
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			v.add(node, path, "Expected a list")
+			return
+		}
+
+		items, _ := schema["items"].(map[string]interface{})
+
+		for i, item := range node.Content {
+			v.validateNode(item, items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case "string":
+		if node.Kind != yaml.ScalarNode {
+			v.add(node, path, "Expected a string")
+		}
+	case "integer":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			v.add(node, path, "Expected an integer")
+		}
+	case "number":
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+			v.add(node, path, "Expected a number")
+		}
+	case "boolean":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			v.add(node, path, "Expected a boolean")
+		}
+	}
+}
+
+// lookup returns the node at the given path of mapping keys and sequence indexes, or the closest existing parent
+func (v *validator) lookup(keys ...interface{}) *yaml.Node {
+	node := v.root
+
+	for _, key := range keys {
+		var next *yaml.Node
+
+		switch k := key.(type) {
+		case string:
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == k {
+						next = node.Content[i+1]
+					}
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && k < len(node.Content) {
+				next = node.Content[k]
+			}
+		}
+
+		if next == nil {
+			return node
+		}
+
+		node = next
+	}
+
+	return node
+}
+
+func (v *validator) validateConfig(config *ProjectConfig) {
+	if config.Deploy == nil {
+		v.add(v.root, "deploy", "No deployments configured")
+	} else {
+		for i, d := range config.Deploy.Helm {
+			v.validateHelm(i, d)
+			v.validateNamespace(d.Namespace, fmt.Sprintf("deploy.helm[%d].namespace", i), "deploy", "helm", i, "namespace")
+		}
+
+		if err := builder.ValidateHelmDependencies(config.Deploy.Helm); err != nil {
+			v.add(v.lookup("deploy", "helm"), "deploy.helm", err.Error())
+		}
+
+		for i, d := range config.Deploy.Kustomize {
+			p := fmt.Sprintf("deploy.kustomize[%d].path", i)
+
+			if d.Path == "" {
+				v.add(v.lookup("deploy", "kustomize", i), p, "Path is required")
+			} else if builder.KustomizationFile(builder.ExpandPath(v.dir, d.Path)) == "" {
+				v.add(v.lookup("deploy", "kustomize", i, "path"), p, fmt.Sprintf("Kustomization not found in path %s", d.Path))
+			}
+
+			v.validateNamespace(d.Namespace, fmt.Sprintf("deploy.kustomize[%d].namespace", i), "deploy", "kustomize", i, "namespace")
+		}
+	}
+
+	for name, environment := range config.Environments {
+		if environment == nil {
+			continue
+		}
+
+		if _, err := environment.SecretsEnvironment(name); err != nil {
+			v.add(v.lookup("environments", name), fmt.Sprintf("environments.%s.secrets", name), err.Error())
+		}
+
+		for deployment := range environment.Values {
+			if config.Deploy == nil || !hasHelmDeployment(config.Deploy.Helm, deployment) {
+				v.add(v.lookup("environments", name, "values", deployment), fmt.Sprintf("environments.%s.values.%s", name, deployment), fmt.Sprintf("Unknown Helm deployment %s", deployment))
+			}
+		}
+	}
+
+	names := map[string]bool{}
+
+	for i, job := range config.Jobs {
+		p := fmt.Sprintf("jobs[%d]", i)
+
+		if err := job.validate(); err != nil {
+			v.add(v.lookup("jobs", i), p, err.Error())
+		}
+
+		if names[job.Name] {
+			v.add(v.lookup("jobs", i, "name"), p+".name", fmt.Sprintf("Duplicate job name %s", job.Name))
+		}
+
+		names[job.Name] = true
+
+		if err := config.validateJobDeployment(&job); err != nil {
+			v.add(v.lookup("jobs", i), p, err.Error())
+		}
+
+		v.validateNamespace(job.Namespace, p+".namespace", "jobs", i, "namespace")
+	}
+
+	for i, test := range config.Test {
+		if test.Image == "" {
+			v.add(v.lookup("test", i), fmt.Sprintf("test[%d].image", i), "Image is required")
+		}
+
+		v.validateNamespace(test.Namespace, fmt.Sprintf("test[%d].namespace", i), "test", i, "namespace")
+	}
+
+	for name := range config.Namespaces {
+		v.validateNamespace(name, fmt.Sprintf("namespaces.%s", name), "namespaces", name)
+	}
+
+	if config.Cluster != nil {
+		if err := config.Cluster.setDefaults(); err != nil {
+			v.add(v.lookup("cluster"), "cluster", err.Error())
+		}
+	}
+
+	if config.Preview != nil {
+		if err := config.Preview.setDefaults(); err != nil {
+			v.add(v.lookup("preview"), "preview", err.Error())
+		}
+	}
+}
+
+func (v *validator) validateHelm(i int, d builder.HelmDeployment) {
+	p := fmt.Sprintf("deploy.helm[%d]", i)
+
+	if d.Name == "" {
+		v.add(v.lookup("deploy", "helm", i), p+".name", "Name is required")
+	}
+
+	if d.Chart == "" {
+		v.add(v.lookup("deploy", "helm", i), p+".chart", "Chart is required")
+	} else if d.Repository == nil && !builder.IsOCIChart(d.Chart) {
+		chartFile := path.Join(builder.ExpandPath(v.dir, d.Chart), "Chart.yaml")
+
+		if !builder.FileExists(chartFile) {
+			v.add(v.lookup("deploy", "helm", i, "chart"), p+".chart", fmt.Sprintf("Chart not found in path %s", d.Chart))
+		} else if d.Version != "" {
+			v.add(v.lookup("deploy", "helm", i, "version"), p+".version", "Version is only supported for charts from a repository or OCI registry")
+		}
+	}
+
+	if d.Version != "" {
+		if _, err := semver.NewConstraint(d.Version); err != nil {
+			v.add(v.lookup("deploy", "helm", i, "version"), p+".version", fmt.Sprintf("Invalid version constraint %s: %v", d.Version, err))
+		}
+	}
+
+	if d.ValuesFile != "" && !builder.FileExists(builder.ExpandPath(v.dir, d.ValuesFile)) {
+		v.add(v.lookup("deploy", "helm", i, "valuesFile"), p+".valuesFile", fmt.Sprintf("Values file %s not found", d.ValuesFile))
+	}
+
+	for x, valuesFile := range d.ValuesFiles {
+		if !builder.FileExists(builder.ExpandPath(v.dir, valuesFile)) {
+			v.add(v.lookup("deploy", "helm", i, "valuesFiles", x), fmt.Sprintf("%s.valuesFiles[%d]", p, x), fmt.Sprintf("Values file %s not found", valuesFile))
+		}
+	}
+
+	if _, err := d.TimeoutDuration(); err != nil {
+		v.add(v.lookup("deploy", "helm", i, "timeout"), p+".timeout", err.Error())
+	}
+
+	if _, err := builder.ExpandValues(builder.NormalizeValues(d.Values).(map[string]interface{})); err != nil {
+		v.add(v.lookup("deploy", "helm", i, "values"), p+".values", err.Error())
+	}
+
+	for key, setFile := range d.SetFile {
+		if !builder.FileExists(builder.ExpandPath(v.dir, setFile)) {
+			v.add(v.lookup("deploy", "helm", i, "setFile", key), fmt.Sprintf("%s.setFile.%s", p, key), fmt.Sprintf("File %s not found", setFile))
+		}
+	}
+
+	if d.Repository == nil {
+		return
+	}
+
+	if err := d.Repository.ValidateCredentials(); err != nil {
+		v.add(v.lookup("deploy", "helm", i, "repository"), p+".repository", err.Error())
+	}
+
+	if d.Repository.CaFile != "" && !builder.FileExists(builder.ExpandPath(v.dir, d.Repository.CaFile)) {
+		v.add(v.lookup("deploy", "helm", i, "repository", "caFile"), p+".repository.caFile", fmt.Sprintf("CA file %s not found", d.Repository.CaFile))
+	}
+
+	// OCI charts only use the repository for credentials and CA bundles
+	if builder.IsOCIChart(d.Chart) {
+		if d.Repository.Name != "" || d.Repository.Url != "" {
+			v.add(v.lookup("deploy", "helm", i, "repository"), p+".repository", "OCI charts are referenced directly, only credentials and CA bundles can be set in repository")
+		}
+	} else {
+		if d.Repository.Name == "" {
+			v.add(v.lookup("deploy", "helm", i, "repository"), p+".repository.name", "Repository name is required")
+		}
+
+		if u, err := url.Parse(d.Repository.Url); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			v.add(v.lookup("deploy", "helm", i, "repository", "url"), p+".repository.url", fmt.Sprintf("Invalid repository url %q", d.Repository.Url))
+		}
+	}
+}
+
+// validateNamespace checks that a namespace, if set, is a valid Kubernetes namespace name
+func (v *validator) validateNamespace(namespace string, path string, keys ...interface{}) {
+	if namespace == "" {
+		return
+	}
+
+	if problems := validation.IsDNS1123Label(namespace); len(problems) > 0 {
+		v.add(v.lookup(keys...), path, fmt.Sprintf("Invalid namespace %q: %s", namespace, strings.Join(problems, ", ")))
+	}
+}
+
+func hasHelmDeployment(deployments []builder.HelmDeployment, name string) bool {
+	for _, d := range deployments {
+		if d.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
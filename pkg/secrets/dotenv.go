@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var dotenvKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*$`)
+
+// ParseDotenv reads KEY=VALUE pairs. Blank lines, comments and an optional export prefix are ignored,
+// double quoted values support escape sequences and single quoted values are taken literally.
+func ParseDotenv(r io.Reader) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		text = strings.TrimPrefix(text, "export ")
+
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+
+		if !ok || !dotenvKeyRegexp.MatchString(key) {
+			return nil, fmt.Errorf("Invalid dotenv entry on line %d", line)
+		}
+
+		value = strings.TrimSpace(value)
+
+		switch {
+		case len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`):
+			unquoted, err := strconv.Unquote(value)
+
+			if err != nil {
+				return nil, fmt.Errorf("Invalid quoted value on line %d: %v", line, err)
+			}
+
+			value = unquoted
+		case len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'"):
+			value = value[1 : len(value)-1]
+		default:
+			// strip trailing comments from unquoted values
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+
+		values[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// FormatDotenv writes values sorted by key, quoting every value so it survives a round trip through ParseDotenv
+func FormatDotenv(values map[string]string) []byte {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+
+	for _, key := range keys {
+		b.WriteString(fmt.Sprintf("%s=%s\n", key, strconv.Quote(values[key])))
+	}
+
+	return []byte(b.String())
+}
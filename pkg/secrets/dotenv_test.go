@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected map[string]string
+		err      string
+	}{
+		{name: "empty", content: "", expected: map[string]string{}},
+		{name: "plain", content: "DB_HOST=localhost\nDB_PORT = 5432\n", expected: map[string]string{"DB_HOST": "localhost", "DB_PORT": "5432"}},
+		{name: "comments and blank lines", content: "# database\n\n  # indented\nDB_HOST=localhost # local only\n", expected: map[string]string{"DB_HOST": "localhost"}},
+		{name: "export prefix", content: "export TOKEN=abc\n", expected: map[string]string{"TOKEN": "abc"}},
+		{name: "double quotes", content: `GREETING="hello \"world\"\n"`, expected: map[string]string{"GREETING": "hello \"world\"\n"}},
+		{name: "single quotes are literal", content: `PATTERN='a\nb # c'`, expected: map[string]string{"PATTERN": `a\nb # c`}},
+		{name: "hash inside a value", content: "URL=http://host/#anchor\n", expected: map[string]string{"URL": "http://host/#anchor"}},
+		{name: "empty value", content: "EMPTY=\n", expected: map[string]string{"EMPTY": ""}},
+		{name: "equals in value", content: "QUERY=a=b\n", expected: map[string]string{"QUERY": "a=b"}},
+		{name: "later keys win", content: "KEY=1\nKEY=2\n", expected: map[string]string{"KEY": "2"}},
+		{name: "dotted and dashed keys", content: "app.name-v2=shop\n", expected: map[string]string{"app.name-v2": "shop"}},
+		{name: "missing separator", content: "KEY=1\nINVALID\n", err: "Invalid dotenv entry on line 2"},
+		{name: "invalid key", content: "1KEY=value\n", err: "Invalid dotenv entry on line 1"},
+		{name: "invalid quoted value", content: `KEY="\q"`, err: "Invalid quoted value on line 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := ParseDotenv(strings.NewReader(test.content))
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestFormatDotenv(t *testing.T) {
+	values := map[string]string{"B": "two words", "A": "1"}
+
+	if formatted := string(FormatDotenv(values)); formatted != "A=\"1\"\nB=\"two words\"\n" {
+		t.Errorf("expected sorted quoted values, got %q", formatted)
+	}
+}
+
+func TestDotenvRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+	}{
+		{name: "empty", values: map[string]string{}},
+		{name: "plain", values: map[string]string{"DB_HOST": "localhost", "DB_PORT": "5432"}},
+		{name: "quotes and escapes", values: map[string]string{"JSON": `{"key": "va\"lue"}`, "BACKSLASH": `C:\path\to`}},
+		{name: "newlines and tabs", values: map[string]string{"CERT": "-----BEGIN-----\nabc\n-----END-----\n", "TAB": "a\tb"}},
+		{name: "comments and spaces", values: map[string]string{"NOTE": "  padded # not a comment  ", "HASH": "#start"}},
+		{name: "unicode", values: map[string]string{"NAME": "Grüße 👋"}},
+		{name: "empty value", values: map[string]string{"EMPTY": ""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := ParseDotenv(bytes.NewReader(FormatDotenv(test.values)))
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(parsed, test.values) {
+				t.Errorf("expected %q, got %q", test.values, parsed)
+			}
+		})
+	}
+}
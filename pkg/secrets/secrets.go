@@ -1,15 +1,121 @@
 package secrets
 
-import "github.com/symbiosis-cloud/symbiosis-go"
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+var Environments = []symbiosis.ProjectEnvironment{
+	symbiosis.ENVIRONMENT_DEVELOPMENT,
+	symbiosis.ENVIRONMENT_PREVIEW,
+	symbiosis.ENVIRONMENT_PRODUCTION,
+}
 
 type SecretsManager struct {
 	client      *symbiosis.Client
 	project     string
 	environment symbiosis.ProjectEnvironment
+	existing    symbiosis.SecretCollection
 }
 
+// GetSecrets returns the secrets of the environment, or all project secrets when no environment is set
 func (m *SecretsManager) GetSecrets() (symbiosis.SecretCollection, error) {
+	if m.environment == "" {
+		return m.client.Secret.GetSecretsByProject(m.project)
+	}
+
 	return m.client.Secret.GetSecretsByProjectAndEnvironment(m.project, m.environment)
 }
 
-func NewSecretManager(client *symbiosis.Client, project string, environment symbiosis.ProjectEnvironment)
+func (m *SecretsManager) GetSecret(key string) (*symbiosis.Secret, error) {
+	secrets, err := m.GetSecrets()
+
+	if err != nil {
+		return nil, err
+	}
+
+	secret, ok := secrets[key]
+
+	if !ok || secret == nil {
+		return nil, fmt.Errorf("Secret %s could not be found in project %s", key, m.project)
+	}
+
+	return secret, nil
+}
+
+// SetSecret creates or updates a secret and enables it for the given environments. An existing secret keeps the
+// environments it is already enabled for, a new secret without environments is enabled for all of them.
+func (m *SecretsManager) SetSecret(key string, value string, environments []symbiosis.ProjectEnvironment) error {
+	if m.existing == nil {
+		existing, err := m.client.Secret.GetSecretsByProject(m.project)
+
+		if err != nil {
+			return err
+		}
+
+		m.existing = existing
+	}
+
+	secret := symbiosis.Secret{Value: value}
+
+	if current, ok := m.existing[key]; ok && current != nil {
+		secret.IsDevelopmentSecret = current.IsDevelopmentSecret
+		secret.IsPreviewSecret = current.IsPreviewSecret
+		secret.IsProductionSecret = current.IsProductionSecret
+	} else if len(environments) == 0 {
+		environments = Environments
+	}
+
+	for _, environment := range environments {
+		switch environment {
+		case symbiosis.ENVIRONMENT_DEVELOPMENT:
+			secret.IsDevelopmentSecret = true
+		case symbiosis.ENVIRONMENT_PREVIEW:
+			secret.IsPreviewSecret = true
+		case symbiosis.ENVIRONMENT_PRODUCTION:
+			secret.IsProductionSecret = true
+		default:
+			return fmt.Errorf("Unknown environment %s", environment)
+		}
+	}
+
+	err := m.client.Secret.Create(m.project, key, secret)
+
+	if err != nil {
+		return err
+	}
+
+	m.existing[key] = &secret
+
+	return nil
+}
+
+// DeleteSecret removes a secret from the project. symbiosis-go has no method for this yet, so the REST endpoint
+// the web console uses is called directly. Both segments are escaped, as secret keys may contain any character.
+func (m *SecretsManager) DeleteSecret(key string) error {
+	return m.client.Call(
+		fmt.Sprintf("/rest/v1/project/%s/secret/%s", url.PathEscape(m.project), url.PathEscape(key)),
+		"Delete",
+		nil,
+	)
+}
+
+func ParseEnvironment(environment string) (symbiosis.ProjectEnvironment, error) {
+	for _, e := range Environments {
+		if string(e) == environment {
+			return e, nil
+		}
+	}
+
+	return "", fmt.Errorf("Unknown environment %s (valid: %v)", environment, Environments)
+}
+
+func NewSecretManager(client *symbiosis.Client, project string, environment symbiosis.ProjectEnvironment) *SecretsManager {
+	return &SecretsManager{
+		client:      client,
+		project:     project,
+		environment: environment,
+	}
+}
@@ -0,0 +1,57 @@
+package secrets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/symbiosis-cloud/symbiosis-go"
+)
+
+func TestDeleteSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		key     string
+		path    string
+		status  int
+		err     bool
+	}{
+		{name: "plain key", project: "web", key: "DB_PASSWORD", path: "/rest/v1/project/web/secret/DB_PASSWORD", status: http.StatusOK},
+		{name: "escaped segments", project: "my project", key: "a/b?c", path: "/rest/v1/project/my%20project/secret/a%2Fb%3Fc", status: http.StatusOK},
+		{name: "missing secret", project: "web", key: "MISSING", path: "/rest/v1/project/web/secret/MISSING", status: http.StatusNotFound, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var method, path string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				path = r.URL.EscapedPath()
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			client, err := symbiosis.NewClientFromAPIKey("test", symbiosis.WithEndpoint(server.URL))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = NewSecretManager(client, test.project, "").DeleteSecret(test.key)
+
+			if test.err != (err != nil) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+
+			if method != http.MethodDelete {
+				t.Errorf("expected method DELETE, got %s", method)
+			}
+
+			if path != test.path {
+				t.Errorf("expected path %s, got %s", test.path, path)
+			}
+		})
+	}
+}
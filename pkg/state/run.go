@@ -0,0 +1,219 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/symbiosis-cloud/cli/pkg/project"
+	"k8s.io/utils/strings/slices"
+)
+
+type RunStage string
+
+const (
+	RUN_STAGE_PENDING RunStage = "pending"
+	RUN_STAGE_CLUSTER RunStage = "cluster"
+	RUN_STAGE_BUILD   RunStage = "build"
+	RUN_STAGE_DEPLOY  RunStage = "deploy"
+)
+
+// stages in the order they are executed by sym run
+var runStages = []string{string(RUN_STAGE_PENDING), string(RUN_STAGE_CLUSTER), string(RUN_STAGE_BUILD), string(RUN_STAGE_DEPLOY)}
+
+type RunOutcome string
+
+const (
+	RUN_OUTCOME_RUNNING   RunOutcome = "running"
+	RUN_OUTCOME_SUCCESS   RunOutcome = "success"
+	RUN_OUTCOME_FAILED    RunOutcome = "failed"
+	RUN_OUTCOME_DESTROYED RunOutcome = "destroyed"
+)
+
+type RunState struct {
+	ID             string                   `json:"id"`
+	Cluster        string                   `json:"cluster"`
+	ClusterCreated bool                     `json:"clusterCreated"`
+	Overrides      project.ClusterOverrides `json:"overrides"`
+	Project        string                   `json:"project"`
+	File           string                   `json:"file"`
+	FileHash       string                   `json:"fileHash"`
+	Namespace      string                   `json:"namespace"`
+	Environment    string                   `json:"environment,omitempty"`
+	Stage          RunStage                 `json:"stage"`
+	Outcome        RunOutcome               `json:"outcome"`
+	Error          string                   `json:"error,omitempty"`
+	Hooks          []*project.HookResult    `json:"hooks,omitempty"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	UpdatedAt      time.Time                `json:"updatedAt"`
+	dir            string
+}
+
+// Reached reports whether the run already completed the given stage
+func (s *RunState) Reached(stage RunStage) bool {
+	return slices.Index(runStages, string(s.Stage)) >= slices.Index(runStages, string(stage))
+}
+
+func (s *RunState) Advance(stage RunStage) error {
+	s.Stage = stage
+
+	return s.Write()
+}
+
+// RecordHook adds the result of a hook command and writes the state, so the hooks of a failed run are kept
+func (s *RunState) RecordHook(result *project.HookResult) error {
+	s.Hooks = append(s.Hooks, result)
+
+	return s.Write()
+}
+
+func (s *RunState) Finish(err error) error {
+	s.Outcome = RUN_OUTCOME_SUCCESS
+	s.Error = ""
+
+	if err != nil {
+		s.Outcome = RUN_OUTCOME_FAILED
+		s.Error = err.Error()
+	}
+
+	return s.Write()
+}
+
+func (s *RunState) Write() error {
+	s.UpdatedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(s.dir, 0700)
+
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(path.Join(s.dir, fmt.Sprintf("%s.json", s.ID)), data, 0600)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewRunState(dir string, id string, cluster string, file string, namespace string, overrides project.ClusterOverrides) (*RunState, error) {
+	hash, err := HashFile(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return &RunState{
+		ID:        id,
+		Cluster:   cluster,
+		Overrides: overrides,
+		File:      file,
+		FileHash:  hash,
+		Namespace: namespace,
+		Stage:     RUN_STAGE_PENDING,
+		Outcome:   RUN_OUTCOME_RUNNING,
+		CreatedAt: now,
+		UpdatedAt: now,
+		dir:       dir,
+	}, nil
+}
+
+// LoadRunState reads a run record. Ids are taken from the command line, so ids that could point outside of
+// dir are rejected.
+func LoadRunState(dir string, id string) (*RunState, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return nil, fmt.Errorf("Invalid run id %q", id)
+	}
+
+	data, err := os.ReadFile(path.Join(dir, fmt.Sprintf("%s.json", id)))
+
+	if err != nil && os.IsNotExist(err) {
+		return nil, fmt.Errorf("Run %s not found", id)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var state *RunState
+
+	err = json.Unmarshal(data, &state)
+
+	if err != nil {
+		return nil, err
+	}
+
+	state.dir = dir
+
+	return state, nil
+}
+
+// ListRunStates returns all recorded runs, most recent first
+func ListRunStates(dir string) ([]*RunState, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil && os.IsNotExist(err) {
+		return []*RunState{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var states []*RunState
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		state, err := LoadRunState(dir, strings.TrimSuffix(entry.Name(), ".json"))
+
+		if err != nil {
+			return nil, err
+		}
+
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].CreatedAt.After(states[j].CreatedAt)
+	})
+
+	return states, nil
+}
+
+// RunStateDir is the directory run records are stored in (~/.symbiosis/runs)
+func RunStateDir() (string, error) {
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(home, ".symbiosis", "runs"), nil
+}
+
+func HashFile(file string) (string, error) {
+	data, err := os.ReadFile(file)
+
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
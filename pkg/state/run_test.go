@@ -0,0 +1,195 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/symbiosis-cloud/cli/pkg/project"
+)
+
+func newTestRunState(t *testing.T) *RunState {
+	file := filepath.Join(t.TempDir(), "sym.yaml")
+
+	err := os.WriteFile(file, []byte("deploy: {}\n"), 0600)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := NewRunState(filepath.Join(t.TempDir(), "runs"), "20221103-ab12", "run-ab12", file, "default", project.ClusterOverrides{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+func TestRunStateReached(t *testing.T) {
+	tests := []struct {
+		stage   RunStage
+		reached []RunStage
+		pending []RunStage
+	}{
+		{stage: RUN_STAGE_PENDING, reached: []RunStage{RUN_STAGE_PENDING}, pending: []RunStage{RUN_STAGE_CLUSTER, RUN_STAGE_BUILD, RUN_STAGE_DEPLOY}},
+		{stage: RUN_STAGE_CLUSTER, reached: []RunStage{RUN_STAGE_PENDING, RUN_STAGE_CLUSTER}, pending: []RunStage{RUN_STAGE_BUILD, RUN_STAGE_DEPLOY}},
+		{stage: RUN_STAGE_BUILD, reached: []RunStage{RUN_STAGE_PENDING, RUN_STAGE_CLUSTER, RUN_STAGE_BUILD}, pending: []RunStage{RUN_STAGE_DEPLOY}},
+		{stage: RUN_STAGE_DEPLOY, reached: []RunStage{RUN_STAGE_PENDING, RUN_STAGE_CLUSTER, RUN_STAGE_BUILD, RUN_STAGE_DEPLOY}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.stage), func(t *testing.T) {
+			state := &RunState{Stage: test.stage}
+
+			for _, stage := range test.reached {
+				if !state.Reached(stage) {
+					t.Errorf("expected stage %s to be reached", stage)
+				}
+			}
+
+			for _, stage := range test.pending {
+				if state.Reached(stage) {
+					t.Errorf("expected stage %s to be pending", stage)
+				}
+			}
+		})
+	}
+}
+
+func TestRunStateTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		stages  []RunStage
+		err     error
+		outcome RunOutcome
+		message string
+	}{
+		{name: "success", stages: []RunStage{RUN_STAGE_CLUSTER, RUN_STAGE_BUILD, RUN_STAGE_DEPLOY}, outcome: RUN_OUTCOME_SUCCESS},
+		{name: "failed build", stages: []RunStage{RUN_STAGE_CLUSTER}, err: fmt.Errorf("build failed"), outcome: RUN_OUTCOME_FAILED, message: "build failed"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := newTestRunState(t)
+
+			if state.Stage != RUN_STAGE_PENDING || state.Outcome != RUN_OUTCOME_RUNNING {
+				t.Fatalf("expected a pending running state, got %s and %s", state.Stage, state.Outcome)
+			}
+
+			for _, stage := range test.stages {
+				err := state.Advance(stage)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				loaded, err := LoadRunState(state.dir, state.ID)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if loaded.Stage != stage || loaded.Outcome != RUN_OUTCOME_RUNNING {
+					t.Errorf("expected stage %s to be written while running, got %s and %s", stage, loaded.Stage, loaded.Outcome)
+				}
+			}
+
+			err := state.Finish(test.err)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := LoadRunState(state.dir, state.ID)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			last := test.stages[len(test.stages)-1]
+
+			if loaded.Stage != last || loaded.Outcome != test.outcome || loaded.Error != test.message {
+				t.Errorf("expected stage %s, outcome %s and error %q, got %s, %s and %q", last, test.outcome, test.message, loaded.Stage, loaded.Outcome, loaded.Error)
+			}
+
+			if loaded.UpdatedAt.Before(loaded.CreatedAt) {
+				t.Errorf("expected the update time to follow the creation time")
+			}
+		})
+	}
+}
+
+func TestRunStateFinishAfterRetry(t *testing.T) {
+	state := newTestRunState(t)
+
+	err := state.Finish(fmt.Errorf("deploy failed"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a resumed run that succeeds clears the error of the failed attempt
+	err = state.Finish(nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Outcome != RUN_OUTCOME_SUCCESS || state.Error != "" {
+		t.Errorf("expected success without error, got %s and %q", state.Outcome, state.Error)
+	}
+}
+
+func TestRunStateRecordHook(t *testing.T) {
+	state := newTestRunState(t)
+
+	result := &project.HookResult{Hook: project.HOOK_PRE_BUILD, Command: "make", Output: []string{"ok"}}
+
+	err := state.RecordHook(result)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadRunState(state.dir, state.ID)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded.Hooks) != 1 || !reflect.DeepEqual(loaded.Hooks[0].Output, result.Output) || loaded.Hooks[0].Command != "make" {
+		t.Errorf("expected the hook result to be written, got %#v", loaded.Hooks)
+	}
+}
+
+func TestLoadRunStateRejectsPaths(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "runs")
+
+	// a record outside of the runs directory that must not be reachable through the id
+	err := os.WriteFile(filepath.Join(root, "outside.json"), []byte(`{"id": "outside"}`), 0600)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"", "../outside", "..", "a/b", `a\b`, "/etc/passwd", "run..1"} {
+		t.Run(id, func(t *testing.T) {
+			_, err := LoadRunState(dir, id)
+
+			if err == nil || !strings.Contains(err.Error(), "Invalid run id") {
+				t.Errorf("expected an invalid run id error, got %v", err)
+			}
+		})
+	}
+
+	_, err = LoadRunState(dir, "20221103-missing")
+
+	if err == nil || !strings.Contains(err.Error(), "Run 20221103-missing not found") {
+		t.Errorf("expected a not found error for a valid id, got %v", err)
+	}
+}
@@ -16,6 +16,30 @@ import (
 	"k8s.io/utils/strings/slices"
 )
 
+// NewClient creates an API client for the configured auth method, it returns nil when no auth method is configured
+func NewClient() (*symbiosis.Client, error) {
+	apiUrl := util.GetEnvOrDefault("SYMBIOSIS_API_URL", symbiosis.APIEndpoint)
+
+	switch viper.GetString("auth.method") {
+	case "api_key":
+		return symbiosis.NewClientFromAPIKey(viper.GetString("auth.api_key"), symbiosis.WithEndpoint(apiUrl))
+	case "token":
+		return symbiosis.NewClientFromToken(viper.GetString("auth.token"), viper.GetString("auth.team_id"), symbiosis.WithEndpoint(apiUrl))
+	}
+
+	return nil, nil
+}
+
+// ExitError makes sym exit with Code instead of 1
+type ExitError struct {
+	Code    int
+	Message string
+}
+
+func (e *ExitError) Error() string {
+	return e.Message
+}
+
 type Command interface {
 	Command() *cobra.Command
 	Init(client *symbiosis.Client, opts *CommandOpts)
@@ -23,7 +47,7 @@ type Command interface {
 
 func Initialise(commands []Command, command *cobra.Command) error {
 
-	isAuthCmd := slices.Contains([]string{"init", "login", "version"}, command.CalledAs())
+	isAuthCmd := slices.Contains([]string{"init", "login", "version", "validate", "doctor"}, command.CalledAs())
 	verbose, err := command.Flags().GetBool("verbose")
 
 	if err != nil {
@@ -36,38 +60,19 @@ func Initialise(commands []Command, command *cobra.Command) error {
 		return err
 	}
 
-	// add commands
-	var c *symbiosis.Client
-
-	authMethod := viper.GetString("auth.method")
-	refreshToken := viper.GetString("auth.refresh_token")
-
 	if !isAuthCmd {
-		err := firebase.ValidateToken(refreshToken)
+		err := firebase.ValidateToken(viper.GetString("auth.refresh_token"))
 
 		if err != nil {
 			log.Fatalf(err.Error())
 		}
 	}
 
-	apiUrl := util.GetEnvOrDefault("SYMBIOSIS_API_URL", symbiosis.APIEndpoint)
-
-	if authMethod == "api_key" {
-		client, err := symbiosis.NewClientFromAPIKey(viper.GetString("auth.api_key"), symbiosis.WithEndpoint(apiUrl))
-
-		if err != nil {
-			log.Fatalf(err.Error())
-		}
-
-		c = client
-	} else if authMethod == "token" {
-		client, err := symbiosis.NewClientFromToken(viper.GetString("auth.token"), viper.GetString("auth.team_id"), symbiosis.WithEndpoint(apiUrl))
-
-		if err != nil {
-			log.Fatalf(err.Error())
-		}
+	// add commands
+	c, err := NewClient()
 
-		c = client
+	if err != nil {
+		log.Fatalf(err.Error())
 	}
 
 	if c == nil && !isAuthCmd {
@@ -82,7 +87,7 @@ func Initialise(commands []Command, command *cobra.Command) error {
 
 	var project *symbiosis.Project
 
-	if selectedProject != "" {
+	if selectedProject != "" && c != nil {
 		p, err := c.Project.Describe(selectedProject)
 
 		if err != nil {
@@ -4,14 +4,17 @@ import "github.com/spf13/cobra"
 
 type DeploymentFlags struct {
 	Namespace          string
+	NamespaceSet       bool
 	IdentityOutputPath string
 	File               string
+	Environment        string
 }
 
 func SetDeploymentFlags(command *cobra.Command) {
-	command.Flags().String("namespace", "default", "Set the deployment namespace (default: default)")
+	command.Flags().String("namespace", "default", "Namespace for deployments, jobs and tests that do not set one (default: default)")
 	command.Flags().String("identity-output-path", "", "Write the generated kubeConfig file to this location")
 	command.Flags().String("file", "sym.yaml", "File to use (default: sym.yaml)")
+	command.Flags().String("env", "", "Environment from the environments section of sym.yaml to use")
 }
 
 func GetDeploymentFlags(command *cobra.Command) (*DeploymentFlags, error) {
@@ -33,10 +36,18 @@ func GetDeploymentFlags(command *cobra.Command) (*DeploymentFlags, error) {
 		return nil, err
 	}
 
+	environment, err := command.Flags().GetString("env")
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &DeploymentFlags{
 		Namespace:          namespace,
+		NamespaceSet:       command.Flags().Changed("namespace"),
 		IdentityOutputPath: identityOutputPath,
 		File:               file,
+		Environment:        environment,
 	}, nil
 
 }
@@ -6,9 +6,11 @@ import (
 )
 
 type CommandOpts struct {
-	Verbose   bool
-	Namespace string
-	Project   *symbiosis.Project
-	Logger    zerolog.Logger
-	Yes       bool
+	Verbose     bool
+	Namespace   string
+	Cluster     string
+	Environment string
+	Project     *symbiosis.Project
+	Logger      zerolog.Logger
+	Yes         bool
 }
@@ -31,6 +31,7 @@ const (
 type TestJob struct {
 	Image          string    `json:"image"`
 	Commands       []string  `json:"commands"`
+	Namespace      string    `json:"namespace,omitempty"`
 	State          TestState `json:"state"`
 	result         *TestResult
 	executionStart time.Time
@@ -54,7 +55,7 @@ type TestResult struct {
 type TestRunner struct {
 	jobs        []*TestJob
 	identity    *identity.ClusterIdentity
-	clientSet   *kubernetes.Clientset
+	clientSet   kubernetes.Interface
 	CommandOpts *symcommand.CommandOpts
 }
 
@@ -71,14 +72,11 @@ func (t *TestRunner) Run(testOutputDir string) error {
 	defer cancel()
 
 	errGroup := new(errgroup.Group)
-	podsApi := t.clientSet.CoreV1().Pods(t.CommandOpts.Namespace)
-
-	var deletePods []string
 
 	for i, job := range t.jobs {
 
 		podName := fmt.Sprintf("test-job-%d", i)
-		deletePods = append(deletePods, podName)
+		podsApi := t.clientSet.CoreV1().Pods(t.namespace(job))
 		job.result = &TestResult{
 			Name:      fmt.Sprintf("test-%d", i),
 			Index:     i,
@@ -97,7 +95,7 @@ func (t *TestRunner) Run(testOutputDir string) error {
 		podSpec := &v1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      podName,
-				Namespace: t.CommandOpts.Namespace,
+				Namespace: t.namespace(job),
 			},
 			Spec: v1.PodSpec{
 				Containers: []v1.Container{
@@ -162,16 +160,15 @@ func (t *TestRunner) Run(testOutputDir string) error {
 		return err
 	}
 
-	if len(deletePods) > 0 {
-		for _, d := range deletePods {
-			err := podsApi.Delete(ctx, d, metav1.DeleteOptions{})
-
-			if err != nil {
-				return err
-			}
+	for i, job := range t.jobs {
+		podName := fmt.Sprintf("test-job-%d", i)
+		err := job.podsApi.Delete(ctx, podName, metav1.DeleteOptions{})
 
-			t.CommandOpts.Logger.Debug().Msgf("Cleaned up pod %s", d)
+		if err != nil {
+			return err
 		}
+
+		t.CommandOpts.Logger.Debug().Msgf("Cleaned up pod %s", podName)
 	}
 
 	var data [][]interface{}
@@ -196,7 +193,27 @@ func (t *TestRunner) Run(testOutputDir string) error {
 
 }
 
-func NewTestRunner(jobs []*TestJob, clientSet *kubernetes.Clientset, opts *symcommand.CommandOpts) (*TestRunner, error) {
+// namespace returns the namespace of a test job, falling back to --namespace
+func (t *TestRunner) namespace(job *TestJob) string {
+	if job.Namespace != "" {
+		return job.Namespace
+	}
+
+	return t.CommandOpts.Namespace
+}
+
+// Namespaces returns the namespaces the test pods run in
+func (t *TestRunner) Namespaces() []string {
+	var namespaces []string
+
+	for _, job := range t.jobs {
+		namespaces = append(namespaces, t.namespace(job))
+	}
+
+	return namespaces
+}
+
+func NewTestRunner(jobs []*TestJob, clientSet kubernetes.Interface, opts *symcommand.CommandOpts) (*TestRunner, error) {
 	return &TestRunner{
 		jobs:        jobs,
 		CommandOpts: opts,
@@ -204,8 +221,8 @@ func NewTestRunner(jobs []*TestJob, clientSet *kubernetes.Clientset, opts *symco
 	}, nil
 }
 
-func NewTestJob(image string, commands []string) *TestJob {
-	return &TestJob{image, commands, TEST_STATE_PENDING, nil, time.Now(), nil, nil, nil}
+func NewTestJob(image string, commands []string, namespace string) *TestJob {
+	return &TestJob{image, commands, namespace, TEST_STATE_PENDING, nil, time.Now(), nil, nil, nil}
 }
 
 // make sure we format duration as float of seconds
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %v", strings.Join(args, " "), err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func GitBranch(dir string) (string, error) {
+	return git(dir, "rev-parse", "--abbrev-ref", "HEAD")
+}
+
+func GitSHA(dir string) (string, error) {
+	return git(dir, "rev-parse", "HEAD")
+}
+
+// GitTag returns the tag pointing at HEAD or an empty string when HEAD is not tagged
+func GitTag(dir string) (string, error) {
+	tag, err := git(dir, "describe", "--tags", "--exact-match", "HEAD")
+
+	if err != nil {
+		return "", nil
+	}
+
+	return tag, nil
+}
@@ -1,10 +1,13 @@
 package util
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/symbiosis-cloud/symbiosis-go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -25,6 +28,38 @@ func GetKubernetesClient(kubeConfig string) (*kubernetes.Clientset, error) {
 	return clientset, nil
 }
 
+// WaitForReadyNodes polls the cluster until at least one node reports a Ready condition
+func WaitForReadyNodes(clientset *kubernetes.Clientset, attempts int, interval time.Duration) error {
+	for it := 0; ; it++ {
+
+		readyNodes := 0
+
+		nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+
+		if err == nil {
+			for _, node := range nodes.Items {
+			inner:
+				for _, condition := range node.Status.Conditions {
+					if condition.Type == "Ready" && condition.Status == "True" {
+						readyNodes++
+						break inner
+					}
+				}
+			}
+		}
+
+		if readyNodes > 0 {
+			return nil
+		}
+
+		if it >= attempts {
+			return fmt.Errorf("Timout trying to check if new cluster is ready")
+		}
+
+		time.Sleep(interval)
+	}
+}
+
 func ParseTaintsAndLabels(taints []string, labels []string) ([]symbiosis.NodeTaint, []symbiosis.NodeLabel, error) {
 	nodeTaints := make([]symbiosis.NodeTaint, len(taints))
 	nodeLabels := make([]symbiosis.NodeLabel, len(labels))